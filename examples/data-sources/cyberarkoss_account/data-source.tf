@@ -0,0 +1,15 @@
+# Look up an account by its account ID
+data "cyberarkoss_account" "by_id" {
+  id = "12_345"
+}
+
+# Search a safe for an account by username and address
+data "cyberarkoss_account" "pgdb" {
+  safe     = "GEN_BY_TF_abc"
+  username = "dbuser"
+  address  = "pgdb.example.com"
+}
+
+output "pgdb_platform" {
+  value = data.cyberarkoss_account.pgdb.platform
+}
@@ -0,0 +1,15 @@
+data "cyberarkoss_account" "pgdb" {
+  safe     = "GEN_BY_TF_abc"
+  username = "dbuser"
+}
+
+data "cyberarkoss_account_secret" "pgdb" {
+  id     = data.cyberarkoss_account.pgdb.id
+  reason = "Terraform pipeline database provisioning"
+}
+
+provider "postgresql" {
+  host     = data.cyberarkoss_account.pgdb.address
+  username = data.cyberarkoss_account.pgdb.username
+  password = data.cyberarkoss_account_secret.pgdb.secret
+}
@@ -0,0 +1,16 @@
+# Every account in a safe modified since the start of the year
+data "cyberarkoss_accounts" "recent" {
+  safe           = "GEN_BY_TF_abc"
+  modified_since = "2024-01-01T00:00:00Z"
+  sort           = "userName asc"
+}
+
+# Accounts whose name, username or address starts with "svc"
+data "cyberarkoss_accounts" "services" {
+  search      = "svc"
+  search_type = "startswith"
+}
+
+output "recent_usernames" {
+  value = [for a in data.cyberarkoss_accounts.recent.accounts : a.username]
+}
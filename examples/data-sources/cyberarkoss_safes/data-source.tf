@@ -0,0 +1,17 @@
+data "cyberarkoss_safes" "linux" {
+  search           = "LINUX"
+  include_accounts = true
+}
+
+# Onboard a service account into every matching safe
+resource "cyberarkoss_dbaccount" "svc" {
+  for_each = { for s in data.cyberarkoss_safes.linux.safes : s.safe_name => s }
+
+  name       = "svc-${each.key}"
+  address    = "pgdb.example.com"
+  username   = "svc_app"
+  platform   = "PostgreSQL"
+  safe       = each.value.safe_name
+  secrettype = "password"
+  secret     = var.svc_password
+}
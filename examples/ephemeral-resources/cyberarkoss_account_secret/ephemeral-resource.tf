@@ -0,0 +1,11 @@
+ephemeral "cyberarkoss_account_secret" "pgdb" {
+  id       = "12_345"
+  reason   = "Terraform pipeline database provisioning"
+  checkout = true
+}
+
+provider "postgresql" {
+  host     = "pgdb.example.com"
+  username = "dbuser"
+  password = ephemeral.cyberarkoss_account_secret.pgdb.secret
+}
@@ -3,4 +3,74 @@ provider "cyberarkoss" {
   domain       = "example-domain"
   clientid     = "automation@cyberark.cloud.aarp0000"
   clientsecret = "SincerelySecure2#24!"
-}
\ No newline at end of file
+}
+
+# Workload identity federation from a CI job, the JWT is exchanged at an Identity OAuth2 app.
+provider "cyberarkoss" {
+  alias       = "ci"
+  tenant      = "aarp0000"
+  domain      = "example-domain"
+  auth_method = "oidc"
+
+  oidc {
+    app_id     = "terraform-ci"
+    token_file = "/var/run/secrets/tokens/cyberark"
+  }
+}
+
+# Token issued outside of terraform, read from CYBERARK_PROVIDER_BEARER_TOKEN.
+provider "cyberarkoss" {
+  alias       = "token"
+  domain      = "example-domain"
+  auth_method = "bearer_token"
+
+  bearer_token {}
+}
+
+# Service user authenticated through an Identity OAuth2 confidential client app.
+provider "cyberarkoss" {
+  alias        = "app"
+  tenant       = "aarp0000"
+  domain       = "example-domain"
+  clientid     = "automation@cyberark.cloud.aarp0000"
+  clientsecret = "SincerelySecure2#24!"
+  auth_method  = "oauth_app"
+
+  oauth_app {
+    app_id = "terraform"
+    scope  = "privilegecloud"
+  }
+}
+
+# Self-hosted PAM, the vault user logs on through the PVWA with LDAP authentication.
+provider "cyberarkoss" {
+  alias        = "onprem"
+  pvwa_url     = "https://pvwa.example.com"
+  ca_cert_file = "/etc/pki/tls/certs/corp-root-ca.pem"
+
+  pvwa {
+    auth_type = "ldap"
+    username  = "svc-terraform"
+    password  = "SincerelySecure2#24!"
+  }
+}
+
+# Settings from the environment, with the client secret mounted from a Kubernetes secret.
+provider "cyberarkoss" {
+  alias             = "k8s"
+  clientsecret_file = "/var/run/secrets/cyberark/clientsecret"
+}
+
+# Runner behind an egress proxy that inspects TLS with the corporate certificate authority.
+provider "cyberarkoss" {
+  alias        = "proxied"
+  tenant       = "aarp0000"
+  domain       = "example-domain"
+  clientid     = "automation@cyberark.cloud.aarp0000"
+  clientsecret = "SincerelySecure2#24!"
+
+  proxy_url          = "http://proxy.example.com:3128"
+  ca_cert_file       = "/etc/pki/tls/certs/corp-root-ca.pem"
+  request_timeout    = 60
+  max_conns_per_host = 8
+}
@@ -0,0 +1,3 @@
+# Accounts are imported by their CyberArk Privilege Cloud account ID.
+# The secret cannot be read from the vault and is recorded from configuration on the next apply.
+terraform import cyberarkoss_account.example 12_345
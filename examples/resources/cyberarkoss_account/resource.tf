@@ -0,0 +1,22 @@
+resource "cyberarkoss_account" "cisco" {
+  name             = "cisco-core-01-admin"
+  address          = "10.0.0.1"
+  username         = "admin"
+  platform         = "CiscoSSH"
+  safe             = "TF_TEST_SAFE"
+  secrettype       = "password"
+  secret           = "SincerelySecure2#24!"
+  sm_manage        = false
+  sm_manage_reason = "Managed by network team."
+
+  platform_properties = {
+    Port        = "22"
+    EnablePort  = "23"
+    Description = "Core switch"
+  }
+
+  remote_machines_access = {
+    remote_machines                      = ["10.0.0.1", "10.0.0.2"]
+    access_restricted_to_remote_machines = true
+  }
+}
@@ -8,7 +8,41 @@ resource "cyberarkoss_dbaccount" "pgdb" {
   secret           = "SincerelySecure2#24!"
   sm_manage        = false
   sm_manage_reason = "No CPM Associated with Safe."
+  engine           = "postgresql"
   db_port          = "8432"
-  db_dsn           = "dsn"
-  dbname           = "dbo.services"
-}
\ No newline at end of file
+  dbname           = "services"
+
+  postgresql = {
+    ssl_mode = "require"
+  }
+}
+
+resource "cyberarkoss_dbaccount" "oracle" {
+  name       = "user-ora"
+  address    = "ora-01.example.com"
+  username   = "app_owner"
+  platform   = "Oracle"
+  safe       = "TF_TEST_SAFE"
+  secrettype = "password"
+  secret     = "SincerelySecure2#24!"
+  engine     = "oracle"
+
+  oracle = {
+    service_name = "ORCLPDB1"
+  }
+}
+
+resource "cyberarkoss_dbaccount" "mongo" {
+  name       = "user-mongo"
+  address    = "mongo-01.example.com"
+  username   = "app"
+  platform   = "MongoDB"
+  safe       = "TF_TEST_SAFE"
+  secrettype = "password"
+  secret     = "SincerelySecure2#24!"
+  engine     = "mongodb"
+
+  mongodb = {
+    replica_set = "rs0"
+  }
+}
@@ -0,0 +1,3 @@
+# GCP service account keys are imported by their CyberArk Privilege Cloud account ID.
+# The key cannot be read from the vault and is recorded from configuration on the next apply.
+terraform import cyberarkoss_gcpaccount.example 12_345
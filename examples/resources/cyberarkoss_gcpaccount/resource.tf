@@ -0,0 +1,22 @@
+resource "cyberarkoss_gcpaccount" "ci" {
+  name                     = "gcp-ci-deployer"
+  safe                     = "TF_TEST_SAFE"
+  service_account_key_file = "${path.module}/ci-deployer.json"
+  sm_manage                = false
+  sm_manage_reason         = "Key rotated by the platform team."
+}
+
+# Onboard a key as soon as it is created in GCP, the base64 encoded private_key is accepted as is.
+resource "google_service_account_key" "deployer" {
+  service_account_id = "projects/my-project/serviceAccounts/deployer@my-project.iam.gserviceaccount.com"
+}
+
+resource "cyberarkoss_gcpaccount" "deployer" {
+  name                = "gcp-deployer"
+  safe                = "TF_TEST_SAFE"
+  service_account_key = google_service_account_key.deployer.private_key
+}
+
+output "deployer_key_id" {
+  value = cyberarkoss_gcpaccount.deployer.private_key_id
+}
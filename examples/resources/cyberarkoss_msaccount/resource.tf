@@ -1,18 +1,23 @@
 resource "cyberarkoss_msaccount" "mskey" {
-  name             = "user-ms"
-  address          = "1.2.3.4"
-  username         = "user-ms"
-  platform         = "MS_TF"
-  safe             = "TF_TEST_SAFE"
-  secrettype       = "password"
-  secret           = "SincerelySecure2#24!"
-  sm_manage        = false
-  sm_manage_reason = "No CPM Associated with Safe."
-  ms_appid         = "ApplicationID"
-  ms_appobjid      = "ApplicationObjectID"
-  ms_keyid         = "KeyID"
-  ms_adid          = "ADKeyID"
-  ms_duration      = "300"
-  ms_pop           = "yes"
-  ms_keydesc       = "key descriptiong with spaces"
-}
\ No newline at end of file
+  name               = "user-ms"
+  address            = "login.microsoftonline.com"
+  username           = "user-ms"
+  platform           = "MS_TF"
+  safe               = "TF_TEST_SAFE"
+  secrettype         = "password"
+  secret             = "SincerelySecure2#24!"
+  sm_manage          = false
+  sm_manage_reason   = "No CPM Associated with Safe."
+  ms_appid           = "3f2b8e4a-5c1d-4e7f-9a6b-2d8c0e1f4a7b"
+  ms_appobjid        = "9d4c1a2e-7b3f-4c8d-a5e6-0f1b2c3d4e5f"
+  ms_keyid           = "b7e6d5c4-3a2b-4c1d-8e9f-0a1b2c3d4e5f"
+  ms_adid            = "72f988bf-86f1-41af-91ab-2d7cd011db47"
+  ms_subscription_id = "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f"
+  ms_duration        = 180
+  ms_pop             = true
+  ms_keydesc         = "key description with spaces"
+}
+
+output "mskey_expiration" {
+  value = cyberarkoss_msaccount.mskey.ms_key_expiration
+}
@@ -0,0 +1,3 @@
+# Safe members are imported by safe name and member name separated by a slash.
+# search_in cannot be read from the vault and is recorded from configuration.
+terraform import cyberarkoss_safe_member.app_team GEN_BY_TF_abc/AppTeam
@@ -0,0 +1,22 @@
+resource "cyberarkoss_safe_member" "app_team" {
+  safe_name                  = cyberarkoss_safeobject.AAM_Test_Safe.safe_name
+  member                     = "AppTeam"
+  member_type                = "group"
+  search_in                  = "Vault"
+  membership_expiration_date = "2025-12-31T00:00:00Z"
+
+  # Start from a preset and override individual permissions
+  permission_level = "read"
+
+  permissions = {
+    view_safe_members             = true
+    requests_authorization_level1 = true
+  }
+}
+
+resource "cyberarkoss_safe_member" "auditors" {
+  safe_name        = cyberarkoss_safeobject.AAM_Test_Safe.safe_name
+  member           = "Auditors"
+  member_type      = "group"
+  permission_level = "auditor"
+}
@@ -0,0 +1,3 @@
+# Safes are imported by their safe URL ID or safe name, followed by a slash and the seed member.
+# Without the seed member, the configured member is added to the safe, or updated when it already is a member, on the next apply.
+terraform import cyberarkoss_safeobject.example GEN_BY_TF_abc/AppOwners
@@ -3,7 +3,7 @@ resource "cyberarkoss_safeobject" "AAM_Test_Safe" {
   safe_desc          = "Description for GEN_BY_TF_abc"
   member             = "demo@cyberark.cloud.aarp0000"
   member_type        = "user"
-  permission_level   = "full" # full, read, approver, manager
+  permission_level   = "full" # full, read, approver, manager, connect_only, auditor, account_onboarder
   retention          = 7
   retention_versions = 7
   purge              = false
@@ -0,0 +1,3 @@
+# SSH key accounts are imported by their CyberArk Privilege Cloud account ID.
+# The private key cannot be read from the vault and is recorded from configuration on the next apply.
+terraform import cyberarkoss_sshkeyaccount.example 12_345
@@ -0,0 +1,13 @@
+resource "cyberarkoss_sshkeyaccount" "deploy" {
+  name             = "web-01-deploy"
+  address          = "web-01.example.com"
+  username         = "deploy"
+  safe             = "TF_TEST_SAFE"
+  private_key_file = pathexpand("~/.ssh/deploy_ed25519")
+  sm_manage        = false
+  sm_manage_reason = "Key rotated by the deployment pipeline."
+}
+
+output "deploy_key_fingerprint" {
+  value = cyberarkoss_sshkeyaccount.deploy.public_key_fingerprint
+}
@@ -0,0 +1,3 @@
+# Windows accounts are imported by their CyberArk Privilege Cloud account ID.
+# The password cannot be read from the vault and is recorded from configuration on the next apply.
+terraform import cyberarkoss_windowsaccount.example 12_345
@@ -0,0 +1,36 @@
+resource "cyberarkoss_windowsaccount" "reconcile" {
+  name         = "corp-reconcile"
+  address      = "corp.example.com"
+  username     = "svc_reconcile"
+  safe         = "TF_TEST_SAFE"
+  secret       = var.reconcile_password
+  logon_domain = "CORP"
+}
+
+resource "cyberarkoss_windowsaccount" "admin" {
+  name         = "corp-admin"
+  address      = "corp.example.com"
+  username     = "admin01"
+  safe         = "TF_TEST_SAFE"
+  secret       = var.admin_password
+  logon_domain = "CORP"
+
+  reconcile_account = {
+    safe = cyberarkoss_windowsaccount.reconcile.safe
+    name = cyberarkoss_windowsaccount.reconcile.name
+  }
+}
+
+resource "cyberarkoss_windowsaccount" "local_admin" {
+  name     = "app-01-administrator"
+  address  = "app-01.corp.example.com"
+  username = "Administrator"
+  platform = "WinServerLocal"
+  safe     = "TF_TEST_SAFE"
+  secret   = var.local_admin_password
+
+  logon_account = {
+    safe = cyberarkoss_windowsaccount.admin.safe
+    name = cyberarkoss_windowsaccount.admin.name
+  }
+}
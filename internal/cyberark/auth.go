@@ -0,0 +1,451 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// Identity service user authenticated with clientid and clientsecret.
+	authMethodClientCredentials = "client_credentials"
+	// Workload identity JWT, such as a CI job token, exchanged at an Identity OAuth2 app.
+	authMethodOIDC = "oidc"
+	// Token issued outside of terraform, used as is.
+	authMethodBearerToken = "bearer_token"
+	// Identity OAuth2 confidential client app authenticated with clientid and clientsecret.
+	authMethodOAuthApp = "oauth_app"
+)
+
+// authMethods lists the accepted auth_method values.
+var authMethods = []string{authMethodClientCredentials, authMethodOIDC, authMethodBearerToken, authMethodOAuthApp}
+
+type oidcAuthModel struct {
+	AppID     htypes.String `tfsdk:"app_id"`
+	Token     htypes.String `tfsdk:"token"`
+	TokenFile htypes.String `tfsdk:"token_file"`
+	Audience  htypes.String `tfsdk:"audience"`
+}
+
+type bearerTokenAuthModel struct {
+	Token htypes.String `tfsdk:"token"`
+}
+
+type oauthAppAuthModel struct {
+	AppID htypes.String `tfsdk:"app_id"`
+	Scope htypes.String `tfsdk:"scope"`
+}
+
+// authMethodAttribute selects how the provider authenticates.
+func authMethodAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "Authentication method: client_credentials authenticates the clientid service user, oidc exchanges a workload identity JWT, bearer_token uses a pre-issued token and oauth_app authenticates clientid against an Identity OAuth2 confidential client app. Defaults to client_credentials.",
+		Optional:    true,
+		Validators: []validator.String{
+			stringvalidator.OneOf(authMethods...),
+		},
+	}
+}
+
+// authBlocks are the per auth_method settings.
+func authBlocks() map[string]schema.Block {
+	return map[string]schema.Block{
+		authMethodOIDC: schema.SingleNestedBlock{
+			Description: "Workload identity federation settings, used when auth_method is oidc. The JWT is read again on every token refresh so rotated tokens are picked up. When neither token nor token_file is set, the token is requested from GitHub Actions.",
+			Attributes: map[string]schema.Attribute{
+				"app_id": schema.StringAttribute{
+					Description: "Application ID of the CyberArk Identity OAuth2 app that trusts the JWT issuer.",
+					Optional:    true,
+				},
+				"token": schema.StringAttribute{
+					Description: "Workload identity JWT, for example a GitLab id_token. Conflicts with token_file.",
+					Optional:    true,
+					Sensitive:   true,
+				},
+				"token_file": schema.StringAttribute{
+					Description: "Path to a file containing the workload identity JWT, for example a projected Kubernetes service account token. Conflicts with token.",
+					Optional:    true,
+				},
+				"audience": schema.StringAttribute{
+					Description: "Audience of the token requested from GitHub Actions. Defaults to the audience configured in GitHub.",
+					Optional:    true,
+				},
+			},
+		},
+		authMethodBearerToken: schema.SingleNestedBlock{
+			Description: "Pre-issued token settings, used when auth_method is bearer_token.",
+			Attributes: map[string]schema.Attribute{
+				"token": schema.StringAttribute{
+					Description: "Bearer token for the vault API. May also be provided via CYBERARK_PROVIDER_BEARER_TOKEN environment variable.",
+					Optional:    true,
+					Sensitive:   true,
+				},
+			},
+		},
+		authMethodOAuthApp: schema.SingleNestedBlock{
+			Description: "Identity OAuth2 confidential client app settings, used when auth_method is oauth_app. The app authenticates clientid and clientsecret.",
+			Attributes: map[string]schema.Attribute{
+				"app_id": schema.StringAttribute{
+					Description: "Application ID of the CyberArk Identity OAuth2 confidential client app.",
+					Optional:    true,
+				},
+				"scope": schema.StringAttribute{
+					Description: "Scope requested from the app, as defined in its token settings.",
+					Optional:    true,
+				},
+			},
+		},
+	}
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ provider.ConfigValidator = authMethodValidator{}
+
+// authMethodValidator checks that only the settings of the selected auth_method are configured.
+type authMethodValidator struct{}
+
+func (v authMethodValidator) Description(_ context.Context) string {
+	return "auth_method settings must match auth_method"
+}
+
+func (v authMethodValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v authMethodValidator) ValidateProvider(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+
+	var method htypes.String
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("auth_method"), &method)...)
+	if resp.Diagnostics.HasError() || method.IsUnknown() {
+		return
+	}
+
+	selected := authMethodClientCredentials
+	if !method.IsNull() {
+		selected = method.ValueString()
+	}
+
+	for _, name := range []string{authMethodOIDC, authMethodBearerToken, authMethodOAuthApp} {
+		var block htypes.Object
+
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &block)...)
+
+		if block.IsNull() || block.IsUnknown() || name == selected {
+			continue
+		}
+
+		resp.Diagnostics.AddAttributeError(
+			path.Root(name),
+			"Authentication settings do not match auth_method",
+			fmt.Sprintf("The %s block is only used when auth_method is %q.", name, name),
+		)
+	}
+
+	if selected != authMethodOIDC {
+		return
+	}
+
+	var token, tokenFile htypes.String
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(authMethodOIDC).AtName("token"), &token)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(authMethodOIDC).AtName("token_file"), &tokenFile)...)
+
+	if !token.IsNull() && !tokenFile.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(authMethodOIDC).AtName("token_file"),
+			"Invalid attribute combination",
+			"Only one of token or token_file can be set.",
+		)
+	}
+}
+
+// identityAppTokenFetcher requests tokens from a CyberArk Identity OAuth2 app with the given grant.
+// Form values are built on every request so credentials read from files or CI are refreshed too.
+func identityAppTokenFetcher(hc *http.Client, tenant string, appID string, form func(ctx context.Context) (url.Values, error), clientID string, clientSecret string) tokenFetcher {
+
+	return func(ctx context.Context) (string, time.Duration, error) {
+
+		values, err := form(ctx)
+		if err != nil {
+			return "", 0, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+tenant+".id.cyberark.cloud/oauth2/token/"+url.PathEscape(appID), strings.NewReader(values.Encode()))
+		if err != nil {
+			return "", 0, fmt.Errorf("unable to construct token request: %w", err)
+		}
+
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		if clientID != "" {
+			req.SetBasicAuth(clientID, clientSecret)
+		}
+
+		tflog.Debug(ctx, "Requesting identity app token", map[string]interface{}{"tenant": tenant, "app_id": appID})
+
+		return doTokenRequest(hc, req)
+	}
+}
+
+// oauthAppForm is the client credentials grant of a confidential client app.
+func oauthAppForm(scope string) func(ctx context.Context) (url.Values, error) {
+	return func(_ context.Context) (url.Values, error) {
+		values := url.Values{"grant_type": {"client_credentials"}}
+
+		if scope != "" {
+			values.Set("scope", scope)
+		}
+
+		return values, nil
+	}
+}
+
+// oidcForm is the JWT bearer grant of a workload identity token, read again for every token request.
+func oidcForm(hc *http.Client, settings *oidcAuthModel) func(ctx context.Context) (url.Values, error) {
+	return func(ctx context.Context) (url.Values, error) {
+		jwt, err := workloadIdentityToken(ctx, hc, settings)
+		if err != nil {
+			return nil, err
+		}
+
+		return url.Values{
+			"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+			"assertion":  {jwt},
+		}, nil
+	}
+}
+
+// workloadIdentityToken returns the configured JWT, the contents of the token file or a token requested from GitHub Actions.
+func workloadIdentityToken(ctx context.Context, hc *http.Client, settings *oidcAuthModel) (string, error) {
+
+	if !settings.Token.IsNull() {
+		return settings.Token.ValueString(), nil
+	}
+
+	if !settings.TokenFile.IsNull() {
+		data, err := os.ReadFile(settings.TokenFile.ValueString())
+		if err != nil {
+			return "", fmt.Errorf("unable to read oidc token file: %w", err)
+		}
+
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	requestURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
+	requestToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+
+	if requestURL == "" || requestToken == "" {
+		return "", errors.New("no oidc token or token_file is set and the GitHub Actions token request variables are not available, grant the job id-token: write permission or configure a token")
+	}
+
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse ACTIONS_ID_TOKEN_REQUEST_URL: %w", err)
+	}
+
+	if !settings.Audience.IsNull() {
+		query := u.Query()
+		query.Set("audience", settings.Audience.ValueString())
+		u.RawQuery = query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to construct GitHub Actions token request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "bearer "+requestToken)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to reach GitHub Actions token service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("unable to read GitHub Actions token response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub Actions token service returned %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var token struct {
+		Value string `json:"value"`
+	}
+
+	if err := json.Unmarshal(body, &token); err != nil || token.Value == "" {
+		return "", errors.New("GitHub Actions token service did not return a token")
+	}
+
+	return token.Value, nil
+}
+
+// bearerTokenFetcher returns a pre-issued token, its lifetime is taken from the exp claim when it is a JWT.
+func bearerTokenFetcher(token string) tokenFetcher {
+
+	return func(_ context.Context) (string, time.Duration, error) {
+
+		expiry, ok := jwtExpiry(token)
+		if !ok {
+			return token, 0, nil
+		}
+
+		lifetime := time.Until(expiry)
+		if lifetime <= 0 {
+			return "", 0, fmt.Errorf("the bearer token expired at %s", expiry.Format(time.RFC3339))
+		}
+
+		return token, lifetime, nil
+	}
+}
+
+// jwtExpiry reads the exp claim of a JWT without verifying it, only to know when the token should be replaced.
+func jwtExpiry(token string) (time.Time, bool) {
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Expiry int64 `json:"exp"`
+	}
+
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Expiry == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(claims.Expiry, 0), true
+}
+
+// authSettings are the provider settings used to authenticate, after environment variable fallbacks are applied.
+type authSettings struct {
+	Method       string
+	Tenant       string
+	ClientID     string
+	ClientSecret string
+	OIDC         *oidcAuthModel
+	BearerToken  string
+	OAuthApp     *oauthAppAuthModel
+}
+
+// validate reports every setting the selected auth_method requires but is not set, on the attribute to set.
+func (s *authSettings) validate() diag.Diagnostics {
+
+	var diags diag.Diagnostics
+
+	required := func(value string, p path.Path, source string) {
+		if value != "" {
+			return
+		}
+
+		diags.AddAttributeError(
+			p,
+			"Missing CyberArk provider configuration",
+			fmt.Sprintf("%s must be set %s when auth_method is %q.", p, source, s.Method),
+		)
+	}
+
+	if s.Method != authMethodBearerToken {
+		required(s.Tenant, path.Root("tenant"), "in the provider block or through the CYBERARK_PROVIDER_TENANT environment variable")
+	}
+
+	switch s.Method {
+	case authMethodOIDC:
+		var appID string
+		if s.OIDC != nil {
+			appID = s.OIDC.AppID.ValueString()
+		}
+
+		required(appID, path.Root(authMethodOIDC).AtName("app_id"), "in the oidc block")
+
+	case authMethodBearerToken:
+		required(s.BearerToken, path.Root(authMethodBearerToken).AtName("token"), "in the bearer_token block or through the CYBERARK_PROVIDER_BEARER_TOKEN environment variable")
+
+	case authMethodClientCredentials, authMethodOAuthApp:
+		required(s.ClientID, path.Root("clientid"), "in the provider block or through the CYBERARK_PROVIDER_CLIENT_ID environment variable")
+		required(s.ClientSecret, path.Root("clientsecret"), "in the provider block, through clientsecret_file or through the CYBERARK_PROVIDER_CLIENT_SECRET environment variable")
+
+		if s.Method == authMethodOAuthApp {
+			var appID string
+			if s.OAuthApp != nil {
+				appID = s.OAuthApp.AppID.ValueString()
+			}
+
+			required(appID, path.Root(authMethodOAuthApp).AtName("app_id"), "in the oauth_app block")
+		}
+	}
+
+	return diags
+}
+
+// tokenFetcher returns the token fetcher of the selected auth_method, the settings must be valid.
+func (s *authSettings) tokenFetcher(hc *http.Client) tokenFetcher {
+
+	switch s.Method {
+	case authMethodOIDC:
+		return identityAppTokenFetcher(hc, s.Tenant, s.OIDC.AppID.ValueString(), oidcForm(hc, s.OIDC), "", "")
+	case authMethodBearerToken:
+		return bearerTokenFetcher(s.BearerToken)
+	case authMethodOAuthApp:
+		return identityAppTokenFetcher(hc, s.Tenant, s.OAuthApp.AppID.ValueString(), oauthAppForm(s.OAuthApp.Scope.ValueString()), s.ClientID, s.ClientSecret)
+	}
+
+	return identityTokenFetcher(hc, s.Tenant, s.ClientID, s.ClientSecret)
+}
+
+// readSecretFile returns the contents of a mounted secret file without the trailing newline added by most tools.
+func readSecretFile(name string) (string, error) {
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+
+	secret := strings.TrimRight(string(data), "\r\n")
+	if secret == "" {
+		return "", fmt.Errorf("%s is empty", name)
+	}
+
+	return secret, nil
+}
+
+// authFailureHint suggests which settings to verify when authentication fails.
+func authFailureHint(s *authSettings) string {
+
+	switch s.Method {
+	case authMethodOIDC:
+		return "Verify oidc.app_id, that the app trusts the issuer and audience of the JWT, and that the JWT has not expired."
+	case authMethodBearerToken:
+		return "Verify bearer_token.token has not expired and was issued for this tenant."
+	case authMethodOAuthApp:
+		return fmt.Sprintf("Verify oauth_app.app_id and scope, and that clientid [%s] is allowed to use the app.", s.ClientID)
+	}
+
+	return fmt.Sprintf("Verify clientid [%s] and clientsecret, and that the service user is not locked or expired.", s.ClientID)
+}
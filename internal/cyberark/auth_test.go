@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+// testJWT returns an unsigned token with the given payload.
+func testJWT(payload string) string {
+	return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".signature"
+}
+
+func TestJWTExpiry(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		token    string
+		expected time.Time
+		ok       bool
+	}{
+		{"expiry claim", testJWT(`{"sub":"user","exp":1704067200}`), time.Unix(1704067200, 0), true},
+		{"padded payload", "header." + base64.URLEncoding.EncodeToString([]byte(`{"exp":1704067200}`)) + ".signature", time.Unix(1704067200, 0), true},
+		{"no expiry claim", testJWT(`{"sub":"user"}`), time.Time{}, false},
+		{"zero expiry", testJWT(`{"exp":0}`), time.Time{}, false},
+		{"invalid expiry claim", testJWT(`{"exp":"tomorrow"}`), time.Time{}, false},
+		{"payload not JSON", testJWT(`user`), time.Time{}, false},
+		{"payload not base64", "header.not base64!.signature", time.Time{}, false},
+		{"opaque token", "3f2a9c0e-8b1d-4e5f-a6b7-c8d9e0f1a2b3", time.Time{}, false},
+		{"too many segments", testJWT(`{"exp":1704067200}`) + ".extra", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			expiry, ok := jwtExpiry(tt.token)
+
+			if ok != tt.ok {
+				t.Fatalf("expected ok %t, got %t", tt.ok, ok)
+			}
+
+			if !expiry.Equal(tt.expected) {
+				t.Errorf("expected expiry %s, got %s", tt.expected, expiry)
+			}
+		})
+	}
+}
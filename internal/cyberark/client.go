@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// vaultClient is the PasswordVault API of the vault managed by the provider.
+// Privilege Cloud and self-hosted PVWA serve the same API behind different hosts and logon flows,
+// resources and data sources only use this interface so they work against either backend.
+type vaultClient interface {
+	// URL returns the endpoint of a path relative to the PasswordVault API root.
+	URL(path string) string
+	// Do sends a request authorized with the current session token.
+	Do(req *http.Request) (*http.Response, error)
+	// Token returns the current session token, refreshing it when needed.
+	Token(ctx context.Context) (string, error)
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ vaultClient = &apiClient{}
+
+// apiClient implements vaultClient for both backends, which only differ in their API root and token.
+type apiClient struct {
+	root   string
+	http   *http.Client
+	tokens *tokenManager
+}
+
+// newPrivilegeCloudClient returns the client of a Privilege Cloud tenant, authorized with Shared Services bearer tokens.
+func newPrivilegeCloudClient(hc *http.Client, domain string, tokens *tokenManager) *apiClient {
+	return newAPIClient(hc, "https://"+domain+".privilegecloud.cyberark.cloud/PasswordVault/API/", "Bearer ", tokens)
+}
+
+// newPVWAClient returns the client of a self-hosted PVWA, authorized with the session token returned by its logon API.
+func newPVWAClient(hc *http.Client, pvwaURL string, tokens *tokenManager) *apiClient {
+	return newAPIClient(hc, pvwaAPIRoot(pvwaURL), "", tokens)
+}
+
+// newAPIClient wraps the transport of hc so every request is authorized, hc without a transport uses http.DefaultTransport.
+func newAPIClient(hc *http.Client, root string, scheme string, tokens *tokenManager) *apiClient {
+
+	base := hc.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &apiClient{
+		root: root,
+		http: &http.Client{
+			Transport: &tokenTransport{
+				base:   base,
+				tokens: tokens,
+				scheme: scheme,
+			},
+			Timeout: hc.Timeout,
+		},
+		tokens: tokens,
+	}
+}
+
+func (c *apiClient) URL(path string) string {
+	return c.root + path
+}
+
+func (c *apiClient) Do(req *http.Request) (*http.Response, error) {
+	return c.http.Do(req)
+}
+
+func (c *apiClient) Token(ctx context.Context) (string, error) {
+	return c.tokens.Token(ctx)
+}
+
+// pvwaAPIRoot returns the PasswordVault API root of a PVWA, pvwa_url may be the server or its PasswordVault application.
+func pvwaAPIRoot(pvwaURL string) string {
+	root := strings.TrimSuffix(pvwaURL, "/")
+	root = strings.TrimSuffix(root, "/PasswordVault")
+	return root + "/PasswordVault/API/"
+}
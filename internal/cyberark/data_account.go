@@ -0,0 +1,284 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	// Hashi Includes
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &accountDataSource{}
+	_ datasource.DataSourceWithConfigure = &accountDataSource{}
+)
+
+// NewAccountDataSource is a helper function to simplify the provider implementation.
+func NewAccountDataSource() datasource.DataSource {
+	return &accountDataSource{}
+}
+
+// accountDataSource is the data source implementation.
+type accountDataSource struct {
+	client vaultClient
+}
+
+// Metadata returns the data source type name.
+func (d *accountDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_account"
+}
+
+type accountDataSourceModel struct {
+	ID                 htypes.String `tfsdk:"id"`
+	Safe               htypes.String `tfsdk:"safe"`
+	Name               htypes.String `tfsdk:"name"`
+	Username           htypes.String `tfsdk:"username"`
+	Address            htypes.String `tfsdk:"address"`
+	Platform           htypes.String `tfsdk:"platform"`
+	SecretType         htypes.String `tfsdk:"secrettype"`
+	PlatformProps      htypes.Map    `tfsdk:"platform_properties"`
+	CreatedTime        htypes.String `tfsdk:"created_time"`
+	Manage             htypes.Bool   `tfsdk:"sm_manage"`
+	ManageReason       htypes.String `tfsdk:"sm_manage_reason"`
+	ManageStatus       htypes.String `tfsdk:"sm_status"`
+	LastModified       htypes.String `tfsdk:"sm_last_modified"`
+	LastReconciled     htypes.String `tfsdk:"sm_last_reconciled"`
+	LastVerified       htypes.String `tfsdk:"sm_last_verified"`
+}
+
+func (d *accountDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Look up a single account onboarded in a CyberArk Privilege Cloud safe, by account ID or by safe and name, username or address.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "CyberArk Privilege Cloud Credential ID. Either id or safe must be set.",
+				Optional: true,
+				Computed: true,
+			},
+			"safe": schema.StringAttribute{
+				Description: "Safe to search for the account. Requires at least one of name, username or address.",
+				Optional: true,
+				Computed: true,
+			},
+			"name": schema.StringAttribute{
+				Description: "Account name. When searching a safe the account name must match exactly.",
+				Optional: true,
+				Computed: true,
+			},
+			"username": schema.StringAttribute{
+				Description: "Username of the credential object. When searching a safe the username must match exactly.",
+				Optional: true,
+				Computed: true,
+			},
+			"address": schema.StringAttribute{
+				Description: "URI, URL or IP associated with the credential. When searching a safe the address must match exactly.",
+				Optional: true,
+				Computed: true,
+			},
+			"platform": schema.StringAttribute{
+				Description: "Management Platform associated with the credential.",
+				Computed: true,
+			},
+			"secrettype": schema.StringAttribute{
+				Description: "Secret type of the credential, password or key.",
+				Computed: true,
+			},
+			"platform_properties": schema.MapAttribute{
+				Description: "All platform account properties of the credential.",
+				ElementType: htypes.StringType,
+				Computed: true,
+			},
+			"created_time": schema.StringAttribute{
+				Description: "Time the account was onboarded into the vault.",
+				Computed: true,
+			},
+			"sm_manage": schema.BoolAttribute{
+				Description: "Whether the credential is automatically managed by the CPM.",
+				Computed: true,
+			},
+			"sm_manage_reason": schema.StringAttribute{
+				Description: "Reason why the credential is not automatically managed.",
+				Computed: true,
+			},
+			"sm_status": schema.StringAttribute{
+				Description: "Status of the last CPM management operation.",
+				Computed: true,
+			},
+			"sm_last_modified": schema.StringAttribute{
+				Description: "Time the secret was last modified.",
+				Computed: true,
+			},
+			"sm_last_reconciled": schema.StringAttribute{
+				Description: "Time the secret was last reconciled.",
+				Computed: true,
+			},
+			"sm_last_verified": schema.StringAttribute{
+				Description: "Time the secret was last verified.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to this datasource.
+func (d *accountDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = data.Client
+}
+
+// Read looks up the account and sets its properties
+func (d *accountDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	var state accountDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var account vaultAccount
+
+	if !state.ID.IsNull() {
+
+		err := vaultRequest(ctx, d.client, http.MethodGet, "Accounts/"+url.PathEscape(state.ID.ValueString()), nil, &account)
+		if err != nil {
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to read account",
+				fmt.Sprintf("Retrieving account [%s] from the vault failed.", state.ID.ValueString()),
+				err,
+			))
+			return
+		}
+
+	} else {
+
+		if state.Safe.IsNull() {
+			resp.Diagnostics.AddError("Missing account lookup filters", "Either id or safe must be set to look up an account.")
+			return
+		}
+
+		if state.Name.IsNull() && state.Username.IsNull() && state.Address.IsNull() {
+			resp.Diagnostics.AddError("Missing account lookup filters", "At least one of name, username or address must be set when searching a safe.")
+			return
+		}
+
+		matches, err := d.searchAccounts(ctx, &state)
+		if err != nil {
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to find account",
+				fmt.Sprintf("Searching safe [%s] failed.", state.Safe.ValueString()),
+				err,
+			))
+			return
+		}
+
+		if len(matches) != 1 {
+			resp.Diagnostics.AddError(
+				"Unable to find account",
+				fmt.Sprintf("Expected exactly one account in safe [%s] matching the configured filters, found %d. Add name, username or address filters to narrow the search.", state.Safe.ValueString(), len(matches)),
+			)
+			return
+		}
+
+		account = matches[0]
+	}
+
+	state.ID = stringValue(account.CredID)
+	state.Safe = stringValue(account.SafeName)
+	state.Name = stringValue(account.Name)
+	state.Username = stringValue(account.UserName)
+	state.Address = stringValue(account.Address)
+	state.Platform = stringValue(account.Platform)
+	state.SecretType = stringValue(account.SecretType)
+	state.CreatedTime = timeValue(account.CreatedTime)
+
+	props, diags := platformPropertiesValue(ctx, account.PlatformProps)
+	resp.Diagnostics.Append(diags...)
+	state.PlatformProps = props
+
+	state.Manage = htypes.BoolNull()
+	state.ManageReason = htypes.StringNull()
+	state.ManageStatus = htypes.StringNull()
+	state.LastModified = htypes.StringNull()
+	state.LastReconciled = htypes.StringNull()
+	state.LastVerified = htypes.StringNull()
+
+	if account.SecretMgmt != nil {
+		state.Manage = boolValue(account.SecretMgmt.AutomaticManagement)
+		state.ManageReason = stringValue(account.SecretMgmt.ManualManagementReason)
+		state.ManageStatus = stringValue(account.SecretMgmt.Status)
+		state.LastModified = timeValue(account.SecretMgmt.ModifiedTime)
+		state.LastReconciled = timeValue(account.SecretMgmt.LastReconcile)
+		state.LastVerified = timeValue(account.SecretMgmt.LastVerified)
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+}
+
+// searchAccounts returns the accounts in the configured safe matching the name, username and address filters exactly.
+func (d *accountDataSource) searchAccounts(ctx context.Context, state *accountDataSourceModel) ([]vaultAccount, error) {
+
+	var terms []string
+
+	for _, v := range []htypes.String{state.Name, state.Username, state.Address} {
+		if !v.IsNull() {
+			terms = append(terms, v.ValueString())
+		}
+	}
+
+	query := url.Values{}
+	query.Set("search", strings.Join(terms, " "))
+	query.Set("filter", "safeName eq "+state.Safe.ValueString())
+
+	accounts, err := listVaultObjects[vaultAccount](ctx, d.client, "Accounts", query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Vault search matches keywords, narrow the result down to exact matches
+	var matches []vaultAccount
+
+	for _, account := range accounts {
+		if matchesFilter(state.Name, account.Name) && matchesFilter(state.Username, account.UserName) && matchesFilter(state.Address, account.Address) {
+			matches = append(matches, account)
+		}
+	}
+
+	return matches, nil
+}
+
+// matchesFilter reports whether an optional filter is unset or matches the vault value exactly.
+func matchesFilter(filter htypes.String, value *string) bool {
+	if filter.IsNull() {
+		return true
+	}
+
+	return value != nil && *value == filter.ValueString()
+}
@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	// Hashi Includes
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &accountSecretDataSource{}
+	_ datasource.DataSourceWithConfigure = &accountSecretDataSource{}
+)
+
+// NewAccountSecretDataSource is a helper function to simplify the provider implementation.
+func NewAccountSecretDataSource() datasource.DataSource {
+	return &accountSecretDataSource{}
+}
+
+// accountSecretDataSource is the data source implementation.
+type accountSecretDataSource struct {
+	client vaultClient
+}
+
+// Metadata returns the data source type name.
+func (d *accountSecretDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_account_secret"
+}
+
+type accountSecretDataSourceModel struct {
+	ID              htypes.String `tfsdk:"id"`
+	Reason          htypes.String `tfsdk:"reason"`
+	TicketingSystem htypes.String `tfsdk:"ticketing_system"`
+	TicketID        htypes.String `tfsdk:"ticket_id"`
+	Version         htypes.Int64  `tfsdk:"version"`
+	Secret          htypes.String `tfsdk:"secret"`
+}
+
+func (d *accountSecretDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Retrieve the secret value of an account onboarded in a CyberArk Privilege Cloud safe. The secret is stored in terraform state, use the cyberarkoss_account_secret ephemeral resource to keep it out of state.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "CyberArk Privilege Cloud Credential ID of the account to retrieve.",
+				Required: true,
+			},
+			"reason": schema.StringAttribute{
+				Description: "Reason for retrieving the secret, required when the safe enforces a retrieval reason.",
+				Optional: true,
+			},
+			"ticketing_system": schema.StringAttribute{
+				Description: "Name of the ticketing system used to validate the retrieval request.",
+				Optional: true,
+			},
+			"ticket_id": schema.StringAttribute{
+				Description: "Ticket ID validated by the ticketing system.",
+				Optional: true,
+			},
+			"version": schema.Int64Attribute{
+				Description: "Version of the secret to retrieve. Defaults to the current version.",
+				Optional: true,
+			},
+			"secret": schema.StringAttribute{
+				Description: "Secret value of the account.",
+				Computed: true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to this datasource.
+func (d *accountSecretDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = data.Client
+}
+
+// Read retrieves the account secret
+func (d *accountSecretDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	var state accountSecretDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body := secretRetrieveRequest{
+		Reason: state.Reason.ValueString(),
+		TicketingSystem: state.TicketingSystem.ValueString(),
+		TicketID: state.TicketID.ValueString(),
+		Version: state.Version.ValueInt64(),
+	}
+
+	secret, err := retrieveVaultSecret(ctx, d.client, state.ID.ValueString(), &body)
+	if err != nil {
+		resp.Diagnostics.Append(secretRetrieveDiagnostic(state.ID.ValueString(), err))
+		return
+	}
+
+	state.Secret = htypes.StringValue(secret)
+
+	// Set state
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+}
+
+// secretRetrieveDiagnostic explains why retrieving an account secret failed.
+// Safes enforcing dual control or ticketing get a dedicated message since terraform cannot complete the approval itself.
+func secretRetrieveDiagnostic(id string, err error) diag.Diagnostic {
+
+	if requiresApproval(err) {
+		return vaultDiagnostic(
+			"Secret retrieval requires approval",
+			fmt.Sprintf("Account [%s] is in a safe that requires dual control approval, a ticket or a retrieval reason before the secret can be retrieved. "+
+				"Request access to the account in Privilege Cloud and run terraform again once the request is confirmed, "+
+				"or set reason, ticketing_system and ticket_id to satisfy the safe policy.", id),
+			err,
+		)
+	}
+
+	return vaultDiagnostic(
+		"Unable to retrieve secret",
+		fmt.Sprintf("Retrieving the secret of account [%s] failed.", id),
+		err,
+	)
+}
@@ -0,0 +1,271 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	// Hashi Includes
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &accountsDataSource{}
+	_ datasource.DataSourceWithConfigure = &accountsDataSource{}
+)
+
+// NewAccountsDataSource is a helper function to simplify the provider implementation.
+func NewAccountsDataSource() datasource.DataSource {
+	return &accountsDataSource{}
+}
+
+// accountsDataSource is the data source implementation.
+type accountsDataSource struct {
+	client vaultClient
+}
+
+// Metadata returns the data source type name.
+func (d *accountsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_accounts"
+}
+
+type accountsDataSourceModel struct {
+	Search        htypes.String      `tfsdk:"search"`
+	SearchType    htypes.String      `tfsdk:"search_type"`
+	Safe          htypes.String      `tfsdk:"safe"`
+	ModifiedSince htypes.String      `tfsdk:"modified_since"`
+	Sort          htypes.String      `tfsdk:"sort"`
+	Accounts      []accountListModel `tfsdk:"accounts"`
+}
+
+// accountListModel holds the base properties of an account returned by the Accounts list API.
+type accountListModel struct {
+	ID            htypes.String `tfsdk:"id"`
+	Name          htypes.String `tfsdk:"name"`
+	Username      htypes.String `tfsdk:"username"`
+	Address       htypes.String `tfsdk:"address"`
+	Platform      htypes.String `tfsdk:"platform"`
+	Safe          htypes.String `tfsdk:"safe"`
+	SecretType    htypes.String `tfsdk:"secrettype"`
+	PlatformProps htypes.Map    `tfsdk:"platform_properties"`
+	CreatedTime   htypes.String `tfsdk:"created_time"`
+	Manage        htypes.Bool   `tfsdk:"sm_manage"`
+	ManageReason  htypes.String `tfsdk:"sm_manage_reason"`
+	ManageStatus  htypes.String `tfsdk:"sm_status"`
+	LastModified  htypes.String `tfsdk:"sm_last_modified"`
+}
+
+func (d *accountsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List accounts onboarded in CyberArk Privilege Cloud safes using search and filter expressions.",
+		Attributes: map[string]schema.Attribute{
+			"search": schema.StringAttribute{
+				Description: "Search keywords matched against account name, username and address.",
+				Optional: true,
+			},
+			"search_type": schema.StringAttribute{
+				Description: "How search keywords are matched: contains or startswith. Defaults to contains.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("contains", "startswith"),
+				},
+			},
+			"safe": schema.StringAttribute{
+				Description: "Only list accounts stored in this safe.",
+				Optional: true,
+			},
+			"modified_since": schema.StringAttribute{
+				Description: "Only list accounts modified at or after this RFC3339 timestamp, for example 2024-01-31T00:00:00Z.",
+				Optional: true,
+			},
+			"sort": schema.StringAttribute{
+				Description: "Comma separated list of properties to sort by, each optionally followed by asc or desc, for example \"userName asc\".",
+				Optional: true,
+			},
+			"accounts": schema.ListNestedAttribute{
+				Description: "Accounts matching the search and filters.",
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "CyberArk Privilege Cloud Credential ID.",
+							Computed: true,
+						},
+						"name": schema.StringAttribute{
+							Description: "Account name.",
+							Computed: true,
+						},
+						"username": schema.StringAttribute{
+							Description: "Username of the credential object.",
+							Computed: true,
+						},
+						"address": schema.StringAttribute{
+							Description: "URI, URL or IP associated with the credential.",
+							Computed: true,
+						},
+						"platform": schema.StringAttribute{
+							Description: "Management Platform associated with the credential.",
+							Computed: true,
+						},
+						"safe": schema.StringAttribute{
+							Description: "Safe where the credential is stored.",
+							Computed: true,
+						},
+						"secrettype": schema.StringAttribute{
+							Description: "Secret type of the credential, password or key.",
+							Computed: true,
+						},
+						"platform_properties": schema.MapAttribute{
+							Description: "All platform account properties of the credential.",
+							ElementType: htypes.StringType,
+							Computed: true,
+						},
+						"created_time": schema.StringAttribute{
+							Description: "Time the account was onboarded into the vault.",
+							Computed: true,
+						},
+						"sm_manage": schema.BoolAttribute{
+							Description: "Whether the credential is automatically managed by the CPM.",
+							Computed: true,
+						},
+						"sm_manage_reason": schema.StringAttribute{
+							Description: "Reason why the credential is not automatically managed.",
+							Computed: true,
+						},
+						"sm_status": schema.StringAttribute{
+							Description: "Status of the last CPM management operation.",
+							Computed: true,
+						},
+						"sm_last_modified": schema.StringAttribute{
+							Description: "Time the secret was last modified.",
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to this datasource.
+func (d *accountsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = data.Client
+}
+
+// Read lists the accounts matching the configured search and filters
+func (d *accountsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	var state accountsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	query := url.Values{}
+
+	if !state.Search.IsNull() {
+		query.Set("search", state.Search.ValueString())
+	}
+
+	if !state.SearchType.IsNull() {
+		query.Set("searchType", state.SearchType.ValueString())
+	}
+
+	if !state.Sort.IsNull() {
+		query.Set("sort", state.Sort.ValueString())
+	}
+
+	// Filter expressions are combined, the vault returns accounts matching all of them
+	var filters []string
+
+	if !state.Safe.IsNull() {
+		filters = append(filters, "safeName eq "+state.Safe.ValueString())
+	}
+
+	if !state.ModifiedSince.IsNull() {
+		since, err := time.Parse(time.RFC3339, state.ModifiedSince.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("modified_since"), "Invalid timestamp", fmt.Sprintf("Expected an RFC3339 timestamp: %s", err.Error()))
+			return
+		}
+
+		filters = append(filters, "modificationTime gte "+strconv.FormatInt(since.Unix(), 10))
+	}
+
+	if len(filters) > 0 {
+		query.Set("filter", strings.Join(filters, " AND "))
+	}
+
+	accounts, err := listVaultObjects[vaultAccount](ctx, d.client, "Accounts", query)
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic("Unable to list accounts", "Listing accounts from the vault failed.", err))
+		return
+	}
+
+	state.Accounts = make([]accountListModel, 0, len(accounts))
+
+	for _, account := range accounts {
+
+		props, diags := platformPropertiesValue(ctx, account.PlatformProps)
+		resp.Diagnostics.Append(diags...)
+
+		item := accountListModel{
+			ID: stringValue(account.CredID),
+			Name: stringValue(account.Name),
+			Username: stringValue(account.UserName),
+			Address: stringValue(account.Address),
+			Platform: stringValue(account.Platform),
+			Safe: stringValue(account.SafeName),
+			SecretType: stringValue(account.SecretType),
+			PlatformProps: props,
+			CreatedTime: timeValue(account.CreatedTime),
+			Manage: htypes.BoolNull(),
+			ManageReason: htypes.StringNull(),
+			ManageStatus: htypes.StringNull(),
+			LastModified: htypes.StringNull(),
+		}
+
+		if account.SecretMgmt != nil {
+			item.Manage = boolValue(account.SecretMgmt.AutomaticManagement)
+			item.ManageReason = stringValue(account.SecretMgmt.ManualManagementReason)
+			item.ManageStatus = stringValue(account.SecretMgmt.Status)
+			item.LastModified = timeValue(account.SecretMgmt.ModifiedTime)
+		}
+
+		state.Accounts = append(state.Accounts, item)
+	}
+
+	// Set state
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+}
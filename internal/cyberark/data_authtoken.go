@@ -4,9 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	// CyberArk Includes
-	cybrapi "github.com/aharriscybr/cybr-api"
-
 	// Hashi Includes
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -27,7 +24,7 @@ func NewTokenDataSource() datasource.DataSource {
 
 // dbAccountResource is the resource implementation.
 type tokenDataSource struct {
-	client *cybrapi.Client
+	client vaultClient
 }
 
 // Metadata returns the resource type name.
@@ -60,19 +57,19 @@ func (d *tokenDataSource) Configure(_ context.Context, req datasource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*cybrapi.Client)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *cybrapi.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 
 		)
 
 		return
 	}
 
-	d.client = client
+	d.client = data.Client
 }
 
 // Return token
@@ -80,7 +77,24 @@ func (d *tokenDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 
 	var state tokenDataSourceModel
 
-	state.Token = htypes.StringValue(*d.client.AuthToken)
+	if d.client == nil {
+		resp.Diagnostics.AddError(
+			"Unable to read authorization token",
+			"The provider has not been configured with an authenticated client. Please check the provider configuration.",
+		)
+		return
+	}
+
+	token, err := d.client.Token(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read authorization token",
+			fmt.Sprintf("Refreshing the provider token failed: %s", err.Error()),
+		)
+		return
+	}
+
+	state.Token = htypes.StringValue(token)
 
 	// Set state
 	diags := resp.State.Set(ctx, &state)
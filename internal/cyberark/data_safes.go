@@ -0,0 +1,242 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	// Hashi Includes
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &safesDataSource{}
+	_ datasource.DataSourceWithConfigure = &safesDataSource{}
+)
+
+// NewSafesDataSource is a helper function to simplify the provider implementation.
+func NewSafesDataSource() datasource.DataSource {
+	return &safesDataSource{}
+}
+
+// safesDataSource is the data source implementation.
+type safesDataSource struct {
+	client vaultClient
+}
+
+// Metadata returns the data source type name.
+func (d *safesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_safes"
+}
+
+type safesDataSourceModel struct {
+	Search          htypes.String   `tfsdk:"search"`
+	IncludeAccounts htypes.Bool     `tfsdk:"include_accounts"`
+	ExtendedDetails htypes.Bool     `tfsdk:"extended_details"`
+	Safes           []safeListModel `tfsdk:"safes"`
+}
+
+// safeListModel mirrors safeObjectModel for safes returned by the Safes list API.
+type safeListModel struct {
+	ID                htypes.String      `tfsdk:"id"`
+	IDNUM             htypes.Int64       `tfsdk:"id_number"`
+	Name              htypes.String      `tfsdk:"safe_name"`
+	Description       htypes.String      `tfsdk:"safe_desc"`
+	Location          htypes.String      `tfsdk:"safe_loc"`
+	CPM               htypes.String      `tfsdk:"cpm_name"`
+	RetentionDays     htypes.Int64       `tfsdk:"retention"`
+	RetentionVersions htypes.Int64       `tfsdk:"retention_versions"`
+	PurgeEnabled      htypes.Bool        `tfsdk:"purge"`
+	Creator           htypes.String      `tfsdk:"creator"`
+	CreationTime      htypes.String      `tfsdk:"creation_time"`
+	Accounts          []safeAccountModel `tfsdk:"accounts"`
+}
+
+type safeAccountModel struct {
+	ID   htypes.String `tfsdk:"id"`
+	Name htypes.String `tfsdk:"name"`
+}
+
+func (d *safesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List CyberArk Privilege Cloud safes visible to the provider user.",
+		Attributes: map[string]schema.Attribute{
+			"search": schema.StringAttribute{
+				Description: "Search keywords matched against safe names and descriptions.",
+				Optional: true,
+			},
+			"include_accounts": schema.BoolAttribute{
+				Description: "Include the accounts stored in each safe.",
+				Optional: true,
+			},
+			"extended_details": schema.BoolAttribute{
+				Description: "Include extended safe details such as the safe creator and creation time.",
+				Optional: true,
+			},
+			"safes": schema.ListNestedAttribute{
+				Description: "Safes matching the search.",
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "CyberArk Privilege Cloud Safe URL ID.",
+							Computed: true,
+						},
+						"id_number": schema.Int64Attribute{
+							Description: "CyberArk Privilege Cloud Safe ID.",
+							Computed: true,
+						},
+						"safe_name": schema.StringAttribute{
+							Description: "The unique name of the Safe.",
+							Computed: true,
+						},
+						"safe_desc": schema.StringAttribute{
+							Description: "The description of the Safe.",
+							Computed: true,
+						},
+						"safe_loc": schema.StringAttribute{
+							Description: "The location of the Safe in the Vault.",
+							Computed: true,
+						},
+						"cpm_name": schema.StringAttribute{
+							Description: "The name of the CPM user who manages the Safe.",
+							Computed: true,
+						},
+						"retention": schema.Int64Attribute{
+							Description: "The number of days that password versions are saved in the Safe.",
+							Computed: true,
+						},
+						"retention_versions": schema.Int64Attribute{
+							Description: "The number of retained versions of every password that is stored in the Safe.",
+							Computed: true,
+						},
+						"purge": schema.BoolAttribute{
+							Description: "Whether or not files are automatically purged after the end of the Object History Retention Period.",
+							Computed: true,
+						},
+						"creator": schema.StringAttribute{
+							Description: "Name of the user who created the Safe. Only set with extended_details.",
+							Computed: true,
+						},
+						"creation_time": schema.StringAttribute{
+							Description: "Time the Safe was created. Only set with extended_details.",
+							Computed: true,
+						},
+						"accounts": schema.ListNestedAttribute{
+							Description: "Accounts stored in the Safe. Only set with include_accounts.",
+							Computed: true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"id": schema.StringAttribute{
+										Description: "CyberArk Privilege Cloud Credential ID.",
+										Computed: true,
+									},
+									"name": schema.StringAttribute{
+										Description: "Account name.",
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to this datasource.
+func (d *safesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = data.Client
+}
+
+// Read lists the safes matching the configured filters
+func (d *safesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	var state safesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	query := url.Values{}
+
+	if !state.Search.IsNull() {
+		query.Set("search", state.Search.ValueString())
+	}
+
+	if !state.IncludeAccounts.IsNull() {
+		query.Set("includeAccounts", strconv.FormatBool(state.IncludeAccounts.ValueBool()))
+	}
+
+	if !state.ExtendedDetails.IsNull() {
+		query.Set("extendedDetails", strconv.FormatBool(state.ExtendedDetails.ValueBool()))
+	}
+
+	safes, err := listVaultObjects[vaultSafe](ctx, d.client, "Safes", query)
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic("Unable to list safes", "Listing safes from the vault failed.", err))
+		return
+	}
+
+	state.Safes = make([]safeListModel, 0, len(safes))
+
+	for _, safe := range safes {
+
+		item := safeListModel{
+			ID: stringValue(safe.URLID),
+			IDNUM: int64Value(safe.NUMBER),
+			Name: stringValue(safe.Name),
+			Description: stringValue(safe.Description),
+			Location: stringValue(safe.Location),
+			CPM: stringValue(safe.CPM),
+			RetentionDays: int64Value(safe.RetentionDays),
+			RetentionVersions: int64Value(safe.RetentionVersions),
+			PurgeEnabled: boolValue(safe.PurgeEnabled),
+			Creator: htypes.StringNull(),
+			CreationTime: timeValue(safe.CreationTime),
+		}
+
+		if safe.Creator != nil {
+			item.Creator = htypes.StringValue(safe.Creator.Name)
+		}
+
+		for _, account := range safe.Accounts {
+			item.Accounts = append(item.Accounts, safeAccountModel{
+				ID: htypes.StringValue(account.ID),
+				Name: htypes.StringValue(account.Name),
+			})
+		}
+
+		state.Safes = append(state.Safes, item)
+	}
+
+	// Set state
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+}
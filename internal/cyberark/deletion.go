@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// Remove the object from the vault when the resource is destroyed.
+	deletionModeDelete = "delete"
+	// Only remove the object from terraform state, leaving it in the vault.
+	deletionModeAbandon = "abandon"
+)
+
+// deletionModeAttribute is the per-resource override of the provider deletion_mode.
+func deletionModeAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "Behavior when this resource is destroyed: delete removes the object from the vault, abandon only removes it from state. Defaults to the provider deletion_mode.",
+		Optional:    true,
+		Validators:  deletionModeValidators(),
+	}
+}
+
+// deletionModeValidators restrict the provider and resource deletion_mode to the supported modes.
+func deletionModeValidators() []validator.String {
+	return []validator.String{
+		stringvalidator.OneOf(deletionModeDelete, deletionModeAbandon),
+	}
+}
+
+func validDeletionMode(mode string) bool {
+	return mode == deletionModeDelete || mode == deletionModeAbandon
+}
+
+// resolveDeletionMode returns the resource deletion mode when set, otherwise the provider default.
+func resolveDeletionMode(resourceMode htypes.String, providerMode string) (string, error) {
+
+	mode := providerMode
+
+	if !resourceMode.IsNull() && !resourceMode.IsUnknown() {
+		mode = resourceMode.ValueString()
+	}
+
+	if mode == "" {
+		mode = deletionModeDelete
+	}
+
+	if !validDeletionMode(mode) {
+		return "", fmt.Errorf("expected deletion_mode to be one of %q or %q, got: %q", deletionModeDelete, deletionModeAbandon, mode)
+	}
+
+	return mode, nil
+}
+
+// removeVaultObject deletes the object at path unless the deletion mode abandons it.
+// Objects that no longer exist in the vault are treated as already removed.
+func removeVaultObject(ctx context.Context, c vaultClient, mode string, path string) error {
+
+	if mode == deletionModeAbandon {
+		tflog.Info(ctx, "Deletion mode is abandon, removing object from state and leaving it in the vault.", map[string]interface{}{"path": path})
+		return nil
+	}
+
+	err := vaultRequest(ctx, c, http.MethodDelete, path, nil, nil)
+	if err != nil {
+		if isNotFound(err) {
+			tflog.Warn(ctx, "Object was already removed from the vault.", map[string]interface{}{"path": path})
+			return nil
+		}
+		return err
+	}
+
+	tflog.Info(ctx, "Removed object from the vault.", map[string]interface{}{"path": path})
+
+	return nil
+}
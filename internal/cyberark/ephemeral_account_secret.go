@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	// Hashi Includes
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ ephemeral.EphemeralResource              = &accountSecretEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &accountSecretEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &accountSecretEphemeralResource{}
+)
+
+// NewAccountSecretEphemeralResource is a helper function to simplify the provider implementation.
+func NewAccountSecretEphemeralResource() ephemeral.EphemeralResource {
+	return &accountSecretEphemeralResource{}
+}
+
+// accountSecretEphemeralResource is the ephemeral resource implementation.
+type accountSecretEphemeralResource struct {
+	client vaultClient
+}
+
+// Metadata returns the ephemeral resource type name.
+func (e *accountSecretEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_account_secret"
+}
+
+type accountSecretEphemeralModel struct {
+	ID              htypes.String `tfsdk:"id"`
+	Reason          htypes.String `tfsdk:"reason"`
+	TicketingSystem htypes.String `tfsdk:"ticketing_system"`
+	TicketID        htypes.String `tfsdk:"ticket_id"`
+	Version         htypes.Int64  `tfsdk:"version"`
+	CheckOut        htypes.Bool   `tfsdk:"checkout"`
+	Secret          htypes.String `tfsdk:"secret"`
+}
+
+// accountSecretPrivate is kept in ephemeral private data so Close knows which account to check in.
+type accountSecretPrivate struct {
+	ID string `json:"id"`
+}
+
+const accountSecretPrivateKey = "account"
+
+func (e *accountSecretEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Retrieve the secret value of an account for the duration of a terraform run. The secret is never written to plan or state files.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "CyberArk Privilege Cloud Credential ID of the account to retrieve.",
+				Required: true,
+			},
+			"reason": schema.StringAttribute{
+				Description: "Reason for retrieving the secret, required when the safe enforces a retrieval reason.",
+				Optional: true,
+			},
+			"ticketing_system": schema.StringAttribute{
+				Description: "Name of the ticketing system used to validate the retrieval request.",
+				Optional: true,
+			},
+			"ticket_id": schema.StringAttribute{
+				Description: "Ticket ID validated by the ticketing system.",
+				Optional: true,
+			},
+			"version": schema.Int64Attribute{
+				Description: "Version of the secret to retrieve. Defaults to the current version.",
+				Optional: true,
+			},
+			"checkout": schema.BoolAttribute{
+				Description: "Check the account back in when terraform closes the ephemeral resource. Use with safes that enforce exclusive access, where retrieving the secret checks the account out.",
+				Optional: true,
+			},
+			"secret": schema.StringAttribute{
+				Description: "Secret value of the account.",
+				Computed: true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the ephemeral resource.
+func (e *accountSecretEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	e.client = data.Client
+}
+
+// Open retrieves the account secret for the current run.
+func (e *accountSecretEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+
+	var result accountSecretEphemeralModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &result)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body := secretRetrieveRequest{
+		Reason: result.Reason.ValueString(),
+		TicketingSystem: result.TicketingSystem.ValueString(),
+		TicketID: result.TicketID.ValueString(),
+		Version: result.Version.ValueInt64(),
+	}
+
+	secret, err := retrieveVaultSecret(ctx, e.client, result.ID.ValueString(), &body)
+	if err != nil {
+		resp.Diagnostics.Append(secretRetrieveDiagnostic(result.ID.ValueString(), err))
+		return
+	}
+
+	result.Secret = htypes.StringValue(secret)
+
+	if result.CheckOut.ValueBool() {
+		private, err := json.Marshal(accountSecretPrivate{ID: result.ID.ValueString()})
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to record checked out account", err.Error())
+			return
+		}
+
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, accountSecretPrivateKey, private)...)
+	}
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &result)...)
+}
+
+// Close checks the account back in when it was checked out by Open.
+func (e *accountSecretEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+
+	data, diags := req.Private.GetKey(ctx, accountSecretPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || data == nil {
+		return
+	}
+
+	var private accountSecretPrivate
+
+	if err := json.Unmarshal(data, &private); err != nil {
+		resp.Diagnostics.AddError("Unable to read checked out account", err.Error())
+		return
+	}
+
+	err := vaultRequest(ctx, e.client, http.MethodPost, "Accounts/"+url.PathEscape(private.ID)+"/CheckIn", nil, nil)
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to check in account",
+			fmt.Sprintf("Checking account [%s] back in failed. The account remains locked until it is released in Privilege Cloud.", private.ID),
+			err,
+		))
+		return
+	}
+
+	tflog.Info(ctx, "Checked in account", map[string]interface{}{"id": private.ID})
+}
@@ -0,0 +1,407 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	cybrtypes "github.com/aharriscybr/cybr-api/pkg/cybr/types"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// memberPermissionsModel is the complete set of safe member permissions.
+type memberPermissionsModel struct {
+	UseAccounts                            htypes.Bool `tfsdk:"use_accounts"`
+	RetrieveAccounts                       htypes.Bool `tfsdk:"retrieve_accounts"`
+	ListAccounts                           htypes.Bool `tfsdk:"list_accounts"`
+	AddAccounts                            htypes.Bool `tfsdk:"add_accounts"`
+	UpdateAccountContent                   htypes.Bool `tfsdk:"update_account_content"`
+	UpdateAccountProperties                htypes.Bool `tfsdk:"update_account_properties"`
+	InitiateCPMAccountManagementOperations htypes.Bool `tfsdk:"initiate_cpm_account_management_operations"`
+	SpecifyNextAccountContent              htypes.Bool `tfsdk:"specify_next_account_content"`
+	RenameAccounts                         htypes.Bool `tfsdk:"rename_accounts"`
+	DeleteAccounts                         htypes.Bool `tfsdk:"delete_accounts"`
+	UnlockAccounts                         htypes.Bool `tfsdk:"unlock_accounts"`
+	ManageSafe                             htypes.Bool `tfsdk:"manage_safe"`
+	ManageSafeMembers                      htypes.Bool `tfsdk:"manage_safe_members"`
+	BackupSafe                             htypes.Bool `tfsdk:"backup_safe"`
+	ViewAuditLog                           htypes.Bool `tfsdk:"view_audit_log"`
+	ViewSafeMembers                        htypes.Bool `tfsdk:"view_safe_members"`
+	RequestsAuthorizationLevel1            htypes.Bool `tfsdk:"requests_authorization_level1"`
+	RequestsAuthorizationLevel2            htypes.Bool `tfsdk:"requests_authorization_level2"`
+	AccessWithoutConfirmation              htypes.Bool `tfsdk:"access_without_confirmation"`
+	CreateFolders                          htypes.Bool `tfsdk:"create_folders"`
+	DeleteFolders                          htypes.Bool `tfsdk:"delete_folders"`
+	MoveAccountsAndFolders                 htypes.Bool `tfsdk:"move_accounts_and_folders"`
+}
+
+// memberPermission names and documents a single permission.
+type memberPermission struct {
+	name        string
+	description string
+}
+
+// memberPermissions lists every permission in the order of the fields of memberPermissionsModel.
+var memberPermissions = []memberPermission{
+	{"use_accounts", "Use accounts but not view their passwords."},
+	{"retrieve_accounts", "Retrieve and view accounts in the Safe."},
+	{"list_accounts", "View the accounts list."},
+	{"add_accounts", "Add accounts to the Safe, including update account properties."},
+	{"update_account_content", "Update existing account content."},
+	{"update_account_properties", "Update existing account properties."},
+	{"initiate_cpm_account_management_operations", "Initiate password management operations through the CPM, such as changing, verifying and reconciling passwords."},
+	{"specify_next_account_content", "Specify the password that is used when the CPM changes the password value."},
+	{"rename_accounts", "Rename existing accounts in the Safe."},
+	{"delete_accounts", "Delete existing passwords in the Safe."},
+	{"unlock_accounts", "Unlock accounts that are locked by other users."},
+	{"manage_safe", "Perform administrative tasks in the Safe, such as updating Safe properties and recovering the Safe."},
+	{"manage_safe_members", "Add and remove Safe members and update their authorizations."},
+	{"backup_safe", "Create a backup of the Safe and its contents."},
+	{"view_audit_log", "View account and user activity in the Safe."},
+	{"view_safe_members", "View the permissions of Safe members."},
+	{"requests_authorization_level1", "Authorize requests to access the Safe at level 1."},
+	{"requests_authorization_level2", "Authorize requests to access the Safe at level 2."},
+	{"access_without_confirmation", "Access the Safe without confirmation from authorized users."},
+	{"create_folders", "Create folders in the Safe."},
+	{"delete_folders", "Delete folders from the Safe."},
+	{"move_accounts_and_folders", "Move accounts and folders in the Safe to different folders and subfolders."},
+}
+
+// fields returns the model permissions in the order of memberPermissions.
+func (m *memberPermissionsModel) fields() []*htypes.Bool {
+	return []*htypes.Bool{
+		&m.UseAccounts,
+		&m.RetrieveAccounts,
+		&m.ListAccounts,
+		&m.AddAccounts,
+		&m.UpdateAccountContent,
+		&m.UpdateAccountProperties,
+		&m.InitiateCPMAccountManagementOperations,
+		&m.SpecifyNextAccountContent,
+		&m.RenameAccounts,
+		&m.DeleteAccounts,
+		&m.UnlockAccounts,
+		&m.ManageSafe,
+		&m.ManageSafeMembers,
+		&m.BackupSafe,
+		&m.ViewAuditLog,
+		&m.ViewSafeMembers,
+		&m.RequestsAuthorizationLevel1,
+		&m.RequestsAuthorizationLevel2,
+		&m.AccessWithoutConfirmation,
+		&m.CreateFolders,
+		&m.DeleteFolders,
+		&m.MoveAccountsAndFolders,
+	}
+}
+
+// vaultPermissionFields returns the vault permissions in the order of memberPermissions.
+func vaultPermissionFields(p *cybrtypes.Permission) []*bool {
+	return []*bool{
+		&p.UseAccounts,
+		&p.RetrieveAccounts,
+		&p.ListAccounts,
+		&p.AddAccounts,
+		&p.UpdateAccountContent,
+		&p.UpdateAccountProperties,
+		&p.InitiateCPMAccountManagementOperations,
+		&p.SpecifyNextAccountContent,
+		&p.RenameAccounts,
+		&p.DeleteAccounts,
+		&p.UnlockAccounts,
+		&p.ManageSafe,
+		&p.ManageSafeMembers,
+		&p.BackupSafe,
+		&p.ViewAuditLog,
+		&p.ViewSafeMembers,
+		&p.RequestsAuthorizationLevel1,
+		&p.RequestsAuthorizationLevel2,
+		&p.AccessWithoutConfirmation,
+		&p.CreateFolders,
+		&p.DeleteFolders,
+		&p.MoveAccountsAndFolders,
+	}
+}
+
+// permissionPresets is the table of named permission levels accepted by permission_level.
+//
+//   - full: every permission except requests_authorization_level2.
+//   - read: use, retrieve and list accounts.
+//   - approver: use, retrieve and list accounts, view and manage safe members.
+//   - manager: every account permission plus managing safe members and viewing the audit log, without safe administration.
+//   - connect_only: list and use accounts to open connections without seeing their passwords.
+//   - auditor: list accounts, view the audit log and view safe members.
+//   - account_onboarder: list and add accounts, update their content and properties and start CPM operations.
+//
+// full, read, approver and manager grant the same permissions as the cybr-api permission builders used by earlier releases.
+var permissionPresets = map[string]cybrtypes.Permission{
+	"full": {
+		ManageSafe:                             true,
+		ManageSafeMembers:                      true,
+		ViewSafeMembers:                        true,
+		ViewAuditLog:                           true,
+		UseAccounts:                            true,
+		RetrieveAccounts:                       true,
+		ListAccounts:                           true,
+		AddAccounts:                            true,
+		UpdateAccountContent:                   true,
+		UpdateAccountProperties:                true,
+		RenameAccounts:                         true,
+		DeleteAccounts:                         true,
+		UnlockAccounts:                         true,
+		InitiateCPMAccountManagementOperations: true,
+		SpecifyNextAccountContent:              true,
+		BackupSafe:                             true,
+		AccessWithoutConfirmation:              true,
+		CreateFolders:                          true,
+		DeleteFolders:                          true,
+		MoveAccountsAndFolders:                 true,
+		RequestsAuthorizationLevel1:            true,
+	},
+	"read": {
+		UseAccounts:      true,
+		RetrieveAccounts: true,
+		ListAccounts:     true,
+	},
+	"approver": {
+		UseAccounts:       true,
+		RetrieveAccounts:  true,
+		ListAccounts:      true,
+		ViewSafeMembers:   true,
+		ManageSafeMembers: true,
+	},
+	"manager": {
+		ManageSafeMembers:                      true,
+		ViewSafeMembers:                        true,
+		ViewAuditLog:                           true,
+		UseAccounts:                            true,
+		RetrieveAccounts:                       true,
+		ListAccounts:                           true,
+		AddAccounts:                            true,
+		UpdateAccountContent:                   true,
+		UpdateAccountProperties:                true,
+		RenameAccounts:                         true,
+		DeleteAccounts:                         true,
+		UnlockAccounts:                         true,
+		InitiateCPMAccountManagementOperations: true,
+		SpecifyNextAccountContent:              true,
+		AccessWithoutConfirmation:              true,
+	},
+	"connect_only": {
+		UseAccounts:  true,
+		ListAccounts: true,
+	},
+	"auditor": {
+		ListAccounts:    true,
+		ViewAuditLog:    true,
+		ViewSafeMembers: true,
+	},
+	"account_onboarder": {
+		ListAccounts:                           true,
+		AddAccounts:                            true,
+		UpdateAccountContent:                   true,
+		UpdateAccountProperties:                true,
+		InitiateCPMAccountManagementOperations: true,
+	},
+}
+
+// permissionPresetNames returns the names of the permission presets in a stable order.
+func permissionPresetNames() []string {
+
+	names := make([]string, 0, len(permissionPresets))
+
+	for name := range permissionPresets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// permissionPresetOf returns the name of the preset granting exactly the given permissions.
+func permissionPresetOf(p *cybrtypes.Permission) (string, bool) {
+
+	for _, name := range permissionPresetNames() {
+		if permissionPresets[name] == *p {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// permissionLevelDescription documents permission_level including the permissions granted by every preset.
+func permissionLevelDescription() string {
+
+	var presets []string
+
+	for _, name := range permissionPresetNames() {
+
+		preset := permissionPresets[name]
+		var granted []string
+
+		for i, field := range vaultPermissionFields(&preset) {
+			if *field {
+				granted = append(granted, memberPermissions[i].name)
+			}
+		}
+
+		presets = append(presets, fmt.Sprintf("%s (%s)", name, strings.Join(granted, ", ")))
+	}
+
+	return "Named permission preset expanded into permissions. Permissions set in the permissions attribute override the preset. Supported presets: " + strings.Join(presets, "; ") + "."
+}
+
+// memberPermissionsAttributes returns the schema of every permission in memberPermissionsModel.
+// Permissions are computed from permission_level when not configured.
+func memberPermissionsAttributes() map[string]schema.Attribute {
+
+	attributes := make(map[string]schema.Attribute, len(memberPermissions))
+
+	for _, permission := range memberPermissions {
+		attributes[permission.name] = schema.BoolAttribute{
+			Description: permission.description,
+			Optional:    true,
+			Computed:    true,
+		}
+	}
+
+	return attributes
+}
+
+// vaultPermission converts the model to the permissions block of the Safe Members API.
+// Unknown and null permissions are not granted.
+func (m *memberPermissionsModel) vaultPermission() *cybrtypes.Permission {
+
+	var p cybrtypes.Permission
+
+	if m == nil {
+		return &p
+	}
+
+	vault := vaultPermissionFields(&p)
+
+	for i, field := range m.fields() {
+		*vault[i] = field.ValueBool()
+	}
+
+	return &p
+}
+
+// equal reports whether both models grant the same known permissions.
+func (m *memberPermissionsModel) equal(other *memberPermissionsModel) bool {
+
+	if m == nil || other == nil {
+		return m == other
+	}
+
+	otherFields := other.fields()
+
+	for i, field := range m.fields() {
+		if !field.Equal(*otherFields[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newMemberPermissionsModel converts the permissions block of the Safe Members API to the model.
+func newMemberPermissionsModel(p *cybrtypes.Permission) *memberPermissionsModel {
+
+	var m memberPermissionsModel
+
+	vault := vaultPermissionFields(p)
+
+	for i, field := range m.fields() {
+		*field = htypes.BoolValue(*vault[i])
+	}
+
+	return &m
+}
+
+// effectivePermissions expands a permission preset and applies the configured permission overrides on top of it.
+// Without a preset every permission that is not overridden is denied.
+func effectivePermissions(level htypes.String, overrides *memberPermissionsModel) (*memberPermissionsModel, error) {
+
+	var preset cybrtypes.Permission
+
+	if !level.IsNull() {
+		var ok bool
+		preset, ok = permissionPresets[level.ValueString()]
+		if !ok {
+			return nil, fmt.Errorf("permission level %q does not match acceptable values: %s", level.ValueString(), strings.Join(permissionPresetNames(), ", "))
+		}
+	}
+
+	effective := newMemberPermissionsModel(&preset)
+
+	if overrides == nil {
+		return effective, nil
+	}
+
+	overrideFields := overrides.fields()
+
+	for i, field := range effective.fields() {
+		switch {
+		case overrideFields[i].IsNull():
+			continue
+		case overrideFields[i].IsUnknown():
+			// The override is only known at apply, the effective permission cannot be planned from the preset
+			*field = htypes.BoolUnknown()
+		default:
+			*field = *overrideFields[i]
+		}
+	}
+
+	return effective, nil
+}
+
+// planMemberPermissions sets the planned permissions to the preset in permission_level with the configured overrides applied.
+// It returns the planned permissions, or nil when they cannot be determined until apply.
+func planMemberPermissions(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) *memberPermissionsModel {
+
+	// Nothing to plan when the resource is destroyed
+	if req.Plan.Raw.IsNull() {
+		return nil
+	}
+
+	var level htypes.String
+	var configured htypes.Object
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("permission_level"), &level)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("permissions"), &configured)...)
+	if resp.Diagnostics.HasError() {
+		return nil
+	}
+
+	if level.IsUnknown() || configured.IsUnknown() {
+		return nil
+	}
+
+	var overrides *memberPermissionsModel
+
+	if !configured.IsNull() {
+		overrides = &memberPermissionsModel{}
+		resp.Diagnostics.Append(configured.As(ctx, overrides, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return nil
+		}
+	}
+
+	effective, err := effectivePermissions(level, overrides)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("permission_level"), "Invalid permission level", err.Error())
+		return nil
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("permissions"), effective)...)
+
+	return effective
+}
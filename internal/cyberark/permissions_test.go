@@ -0,0 +1,253 @@
+package provider
+
+import (
+	"testing"
+
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// permissionOverrides returns a model with the given permissions set and every other permission null, as in configuration.
+func permissionOverrides(set map[string]htypes.Bool) *memberPermissionsModel {
+
+	var m memberPermissionsModel
+
+	for i, field := range m.fields() {
+		value, ok := set[memberPermissions[i].name]
+		if !ok {
+			value = htypes.BoolNull()
+		}
+
+		*field = value
+	}
+
+	return &m
+}
+
+// grantedPermissions returns the names of the permissions granted by the model.
+func grantedPermissions(m *memberPermissionsModel) map[string]bool {
+
+	granted := map[string]bool{}
+
+	for i, field := range m.fields() {
+		if field.ValueBool() {
+			granted[memberPermissions[i].name] = true
+		}
+	}
+
+	return granted
+}
+
+func TestPermissionPresets(t *testing.T) {
+
+	tests := []struct {
+		level   string
+		granted []string
+	}{
+		{"read", []string{"use_accounts", "retrieve_accounts", "list_accounts"}},
+		{"connect_only", []string{"use_accounts", "list_accounts"}},
+		{"auditor", []string{"list_accounts", "view_audit_log", "view_safe_members"}},
+		{"approver", []string{"use_accounts", "retrieve_accounts", "list_accounts", "view_safe_members", "manage_safe_members"}},
+		{"account_onboarder", []string{"list_accounts", "add_accounts", "update_account_content", "update_account_properties", "initiate_cpm_account_management_operations"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+
+			effective, err := effectivePermissions(htypes.StringValue(tt.level), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			granted := grantedPermissions(effective)
+
+			if len(granted) != len(tt.granted) {
+				t.Errorf("expected %d granted permissions, got %d: %v", len(tt.granted), len(granted), granted)
+			}
+
+			for _, name := range tt.granted {
+				if !granted[name] {
+					t.Errorf("expected %s to be granted", name)
+				}
+			}
+		})
+	}
+}
+
+func TestPermissionPresetsFull(t *testing.T) {
+
+	effective, err := effectivePermissions(htypes.StringValue("full"), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	granted := grantedPermissions(effective)
+
+	for _, permission := range memberPermissions {
+		if permission.name == "requests_authorization_level2" {
+			if granted[permission.name] {
+				t.Errorf("expected %s to be denied", permission.name)
+			}
+			continue
+		}
+
+		if !granted[permission.name] {
+			t.Errorf("expected %s to be granted", permission.name)
+		}
+	}
+}
+
+func TestPermissionPresetsManager(t *testing.T) {
+
+	effective, err := effectivePermissions(htypes.StringValue("manager"), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	granted := grantedPermissions(effective)
+
+	for _, name := range []string{"manage_safe", "backup_safe", "requests_authorization_level1", "requests_authorization_level2"} {
+		if granted[name] {
+			t.Errorf("expected %s to be denied", name)
+		}
+	}
+
+	for _, name := range []string{"manage_safe_members", "view_audit_log", "delete_accounts", "access_without_confirmation"} {
+		if !granted[name] {
+			t.Errorf("expected %s to be granted", name)
+		}
+	}
+}
+
+func TestPermissionPresetOf(t *testing.T) {
+
+	custom := permissionPresets["read"]
+	custom.ViewAuditLog = true
+
+	for _, name := range permissionPresetNames() {
+		preset := permissionPresets[name]
+
+		if level, ok := permissionPresetOf(&preset); !ok || level != name {
+			t.Errorf("expected preset %s, got %s", name, level)
+		}
+	}
+
+	if level, ok := permissionPresetOf(&custom); ok {
+		t.Errorf("expected no preset for custom permissions, got %s", level)
+	}
+}
+
+func TestEffectivePermissions(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		level     htypes.String
+		overrides *memberPermissionsModel
+		expected  map[string]htypes.Bool
+		err       bool
+	}{
+		{
+			name:  "no preset denies everything",
+			level: htypes.StringNull(),
+			expected: map[string]htypes.Bool{
+				"use_accounts":  htypes.BoolValue(false),
+				"manage_safe":   htypes.BoolValue(false),
+				"list_accounts": htypes.BoolValue(false),
+			},
+		},
+		{
+			name:      "override grants on top of the preset",
+			level:     htypes.StringValue("read"),
+			overrides: permissionOverrides(map[string]htypes.Bool{"view_audit_log": htypes.BoolValue(true)}),
+			expected: map[string]htypes.Bool{
+				"use_accounts":   htypes.BoolValue(true),
+				"view_audit_log": htypes.BoolValue(true),
+				"manage_safe":    htypes.BoolValue(false),
+			},
+		},
+		{
+			name:      "override denies a preset permission",
+			level:     htypes.StringValue("read"),
+			overrides: permissionOverrides(map[string]htypes.Bool{"retrieve_accounts": htypes.BoolValue(false)}),
+			expected: map[string]htypes.Bool{
+				"use_accounts":      htypes.BoolValue(true),
+				"retrieve_accounts": htypes.BoolValue(false),
+			},
+		},
+		{
+			name:      "overrides without preset",
+			level:     htypes.StringNull(),
+			overrides: permissionOverrides(map[string]htypes.Bool{"list_accounts": htypes.BoolValue(true)}),
+			expected: map[string]htypes.Bool{
+				"list_accounts": htypes.BoolValue(true),
+				"use_accounts":  htypes.BoolValue(false),
+			},
+		},
+		{
+			name:      "unknown override is planned unknown",
+			level:     htypes.StringValue("read"),
+			overrides: permissionOverrides(map[string]htypes.Bool{"retrieve_accounts": htypes.BoolUnknown()}),
+			expected: map[string]htypes.Bool{
+				"retrieve_accounts": htypes.BoolUnknown(),
+				"use_accounts":      htypes.BoolValue(true),
+				"manage_safe":       htypes.BoolValue(false),
+			},
+		},
+		{
+			name:  "invalid preset",
+			level: htypes.StringValue("owner"),
+			err:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			effective, err := effectivePermissions(tt.level, tt.overrides)
+
+			if tt.err {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			for i, field := range effective.fields() {
+				expected, ok := tt.expected[memberPermissions[i].name]
+				if ok && !field.Equal(expected) {
+					t.Errorf("expected %s to be %s, got %s", memberPermissions[i].name, expected, *field)
+				}
+			}
+		})
+	}
+}
+
+func TestMemberPermissionsEqual(t *testing.T) {
+
+	readPreset := permissionPresets["read"]
+	fullPreset := permissionPresets["full"]
+	read := newMemberPermissionsModel(&readPreset)
+
+	tests := []struct {
+		name     string
+		a        *memberPermissionsModel
+		b        *memberPermissionsModel
+		expected bool
+	}{
+		{"same permissions", read, newMemberPermissionsModel(&readPreset), true},
+		{"different permissions", read, newMemberPermissionsModel(&fullPreset), false},
+		{"both nil", nil, nil, true},
+		{"one nil", read, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.a.equal(tt.b); actual != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, actual)
+			}
+		})
+	}
+}
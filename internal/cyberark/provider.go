@@ -2,10 +2,17 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
 
-	cybrapi "github.com/aharriscybr/cybr-api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -16,6 +23,8 @@ import (
 // Ensure the implementation satisfies the expected interfaces.
 var (
 	_ provider.Provider = &cyberarkProvider{}
+	_ provider.ProviderWithEphemeralResources = &cyberarkProvider{}
+	_ provider.ProviderWithConfigValidators = &cyberarkProvider{}
 )
 
 func New(version string) func() provider.Provider {
@@ -30,11 +39,26 @@ type cyberarkProvider struct {
 	version string
 }
 
+// providerData is shared with every resource and data source through Configure.
+type providerData struct {
+	Client vaultClient
+	DeletionMode string
+}
+
 type confModel struct {
 	Tenant htypes.String `tfsdk:"tenant"`
 	ClientID htypes.String `tfsdk:"clientid"`
 	ClientSecret htypes.String `tfsdk:"clientsecret"`
+	ClientSecretFile htypes.String `tfsdk:"clientsecret_file"`
 	Domain htypes.String `tfsdk:"domain"`
+	DeletionMode htypes.String `tfsdk:"deletion_mode"`
+	AuthMethod htypes.String `tfsdk:"auth_method"`
+	OIDC *oidcAuthModel `tfsdk:"oidc"`
+	BearerToken *bearerTokenAuthModel `tfsdk:"bearer_token"`
+	OAuthApp *oauthAppAuthModel `tfsdk:"oauth_app"`
+	PVWAURL htypes.String `tfsdk:"pvwa_url"`
+	PVWA *pvwaAuthModel `tfsdk:"pvwa"`
+	transportModel
 }
 
 // Metadata returns the provider type name.
@@ -47,26 +71,61 @@ func (p *cyberarkProvider) Metadata(_ context.Context, _ provider.MetadataReques
 func (p *cyberarkProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
 	
 	resp.Schema = schema.Schema{
-		Description: "Configure tenant used to onboard account types into CyberArk Privilege Cloud Vault",
+		Description: "Configure the CyberArk Privilege Cloud tenant or self-hosted PVWA used to onboard account types into the vault",
 		Attributes: map[string]schema.Attribute{
 			"tenant": schema.StringAttribute{
-				Description: "CyberArk Shared Services Tenant.",
-				Required: true,
+				Description: "CyberArk Shared Services Tenant. Required unless auth_method is bearer_token or pvwa_url is set. May also be provided via CYBERARK_PROVIDER_TENANT environment variable.",
+				Optional: true,
 			},
 			"clientid": schema.StringAttribute{
-				Description: "CyberArk Client ID, formatted as username@cyberark.cloud.tenant.",
-				Required: true,
+				Description: "CyberArk Client ID, formatted as username@cyberark.cloud.tenant. Required when auth_method is client_credentials or oauth_app. May also be provided via CYBERARK_PROVIDER_CLIENT_ID environment variable.",
+				Optional: true,
 			},
 			"clientsecret": schema.StringAttribute{
-				Description: "CyberArk Client ID Password.",
-				Required: true,
+				Description: "CyberArk Client ID Password. Required when auth_method is client_credentials or oauth_app, unless clientsecret_file is set. May also be provided via CYBERARK_PROVIDER_CLIENT_SECRET environment variable.",
+				Optional: true,
 				Sensitive: true,
 			},
+			"clientsecret_file": schema.StringAttribute{
+				Description: "Path to a file containing the CyberArk Client ID Password, for example a mounted Kubernetes secret. Conflicts with clientsecret. May also be provided via CYBERARK_PROVIDER_CLIENT_SECRET_FILE environment variable.",
+				Optional: true,
+			},
 			"domain": schema.StringAttribute{
-				Description: "CyberArk Privilege Cloud Domain.",
-				Required: true,
+				Description: "CyberArk Privilege Cloud Domain. Required unless pvwa_url is set. May also be provided via CYBERARK_PROVIDER_DOMAIN environment variable.",
+				Optional: true,
 			},
+			"deletion_mode": schema.StringAttribute{
+				Description: "Default behavior when a resource is destroyed: delete removes the object from the vault, abandon only removes it from state. Defaults to delete and can be overridden per resource.",
+				Optional: true,
+				Validators: deletionModeValidators(),
+			},
+			"auth_method": authMethodAttribute(),
+			"pvwa_url": pvwaURLAttribute(),
 		},
+		Blocks: providerBlocks(),
+	}
+
+	for name, attribute := range transportAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// providerBlocks returns the auth_method settings blocks and the PVWA logon block.
+func providerBlocks() map[string]schema.Block {
+	blocks := authBlocks()
+	blocks["pvwa"] = pvwaBlock()
+	return blocks
+}
+
+// ConfigValidators validate attribute combinations before Configure.
+func (p *cyberarkProvider) ConfigValidators(_ context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		authMethodValidator{},
+		providervalidator.Conflicting(path.MatchRoot("clientsecret"), path.MatchRoot("clientsecret_file")),
+		providervalidator.Conflicting(path.MatchRoot("ca_cert_file"), path.MatchRoot("ca_cert_pem")),
+		providervalidator.Conflicting(path.MatchRoot("pvwa_url"), path.MatchRoot("tenant")),
+		providervalidator.Conflicting(path.MatchRoot("pvwa_url"), path.MatchRoot("domain")),
+		providervalidator.Conflicting(path.MatchRoot("pvwa_url"), path.MatchRoot("auth_method")),
 	}
 }
 
@@ -84,10 +143,52 @@ func (p *cyberarkProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
-	var t, cid, csec, do string
+	// Values computed by other resources are unknown until apply, the client cannot be built from them
+	for _, setting := range []struct {
+		value attr.Value
+		name string
+		env string
+	}{
+		{hconfig.Tenant, "tenant", "CYBERARK_PROVIDER_TENANT"},
+		{hconfig.ClientID, "clientid", "CYBERARK_PROVIDER_CLIENT_ID"},
+		{hconfig.ClientSecret, "clientsecret", "CYBERARK_PROVIDER_CLIENT_SECRET"},
+		{hconfig.ClientSecretFile, "clientsecret_file", "CYBERARK_PROVIDER_CLIENT_SECRET_FILE"},
+		{hconfig.Domain, "domain", "CYBERARK_PROVIDER_DOMAIN"},
+		{hconfig.PVWAURL, "pvwa_url", "CYBERARK_PROVIDER_PVWA_URL"},
+		{hconfig.ProxyURL, "proxy_url", ""},
+		{hconfig.CACertFile, "ca_cert_file", ""},
+		{hconfig.CACertPEM, "ca_cert_pem", ""},
+		{hconfig.InsecureSkipVerify, "insecure_skip_verify", ""},
+		{hconfig.RequestTimeout, "request_timeout", ""},
+		{hconfig.MaxIdleConns, "max_idle_conns", ""},
+		{hconfig.MaxIdleConnsPerHost, "max_idle_conns_per_host", ""},
+		{hconfig.MaxConnsPerHost, "max_conns_per_host", ""},
+	} {
+		if !setting.value.IsUnknown() {
+			continue
+		}
+
+		remedy := "Either apply the source of the value first or set the value statically in the configuration."
+		if setting.env != "" {
+			remedy = fmt.Sprintf("Either apply the source of the value first, set the value statically in the configuration, or use the %s environment variable.", setting.env)
+		}
+
+		resp.Diagnostics.AddAttributeError(
+			path.Root(setting.name),
+			"Unknown CyberArk provider configuration",
+			fmt.Sprintf("The provider cannot create the CyberArk client as there is an unknown configuration value for %s. %s", setting.name, remedy),
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var t, cid, csec, csecFile, do string
 	t = os.Getenv("CYBERARK_PROVIDER_TENANT")
 	cid = os.Getenv("CYBERARK_PROVIDER_CLIENT_ID")
 	csec = os.Getenv("CYBERARK_PROVIDER_CLIENT_SECRET")
+	csecFile = os.Getenv("CYBERARK_PROVIDER_CLIENT_SECRET_FILE")
 	do = os.Getenv("CYBERARK_PROVIDER_DOMAIN")
 
 	// Override
@@ -99,37 +200,240 @@ func (p *cyberarkProvider) Configure(ctx context.Context, req provider.Configure
 	}
 	if !hconfig.ClientSecret.IsNull() {
 		csec = hconfig.ClientSecret.ValueString()
+		csecFile = ""
+	}
+	if !hconfig.ClientSecretFile.IsNull() {
+		csecFile = hconfig.ClientSecretFile.ValueString()
+		csec = ""
 	}
 	if !hconfig.Domain.IsNull() {
 		do = hconfig.Domain.ValueString()
 	}
 
-	client, err := cybrapi.NewClient(&t, &do, &cid, &csec)
+	deletionMode := deletionModeDelete
+	if !hconfig.DeletionMode.IsNull() {
+		deletionMode = hconfig.DeletionMode.ValueString()
+	}
+
+	if !validDeletionMode(deletionMode) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("deletion_mode"),
+			"Invalid deletion mode",
+			fmt.Sprintf("Expected one of %q or %q, got: %q.", deletionModeDelete, deletionModeAbandon, deletionMode),
+		)
+		return
+	}
+
+	pvwa := pvwaSettings{
+		URL: os.Getenv("CYBERARK_PROVIDER_PVWA_URL"),
+		AuthType: pvwaAuthCyberArk,
+		Username: os.Getenv("CYBERARK_PROVIDER_PVWA_USERNAME"),
+		Password: os.Getenv("CYBERARK_PROVIDER_PVWA_PASSWORD"),
+	}
+
+	if !hconfig.PVWAURL.IsNull() {
+		pvwa.URL = hconfig.PVWAURL.ValueString()
+	} else if pvwa.URL != "" && (!hconfig.Tenant.IsNull() || !hconfig.Domain.IsNull() || !hconfig.AuthMethod.IsNull()) {
+		// Privilege Cloud settings of the provider block win over a PVWA URL only set in the environment
+		resp.Diagnostics.AddWarning(
+			"Ignoring CYBERARK_PROVIDER_PVWA_URL",
+			fmt.Sprintf("The provider block configures Privilege Cloud with tenant, domain or auth_method, the PVWA URL [%s] set in the CYBERARK_PROVIDER_PVWA_URL environment variable is ignored. Unset the environment variable or set pvwa_url in the provider block instead.", pvwa.URL),
+		)
+		pvwa.URL = ""
+	}
+
+	if hconfig.PVWA != nil {
+		if !hconfig.PVWA.AuthType.IsNull() {
+			pvwa.AuthType = hconfig.PVWA.AuthType.ValueString()
+		}
+		if !hconfig.PVWA.Username.IsNull() {
+			pvwa.Username = hconfig.PVWA.Username.ValueString()
+		}
+		if !hconfig.PVWA.Password.IsNull() {
+			pvwa.Password = hconfig.PVWA.Password.ValueString()
+		}
+	}
+
+	hc, settingPath, err := newHTTPClient(&hconfig.transportModel)
 	if err != nil {
-		tflog.Error(ctx, "Error configuring new client.")
+		resp.Diagnostics.AddAttributeError(
+			settingPath,
+			"Unable to configure CyberArk client",
+			fmt.Sprintf("The provider HTTP client cannot be configured: %s.", err.Error()),
+		)
+		return
+	}
+
+	if hconfig.InsecureSkipVerify.ValueBool() {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("insecure_skip_verify"),
+			"TLS certificate verification is disabled",
+			"The provider does not verify the certificates of CyberArk endpoints, credentials and secrets can be intercepted by anyone able to reach the network path. Trust the required certificate authority with ca_cert_file or ca_cert_pem instead.",
+		)
+	}
+
+	var client vaultClient
+
+	// A PVWA URL selects the self-hosted vault, tenant and domain are only used by Privilege Cloud
+	if pvwa.URL != "" {
+		resp.Diagnostics.Append(pvwa.validate()...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		client = configurePVWA(ctx, hc, &pvwa, &resp.Diagnostics)
+	} else {
+		auth := authSettings{
+			Method: authMethodClientCredentials,
+			Tenant: t,
+			ClientID: cid,
+			ClientSecret: csec,
+			OIDC: hconfig.OIDC,
+			BearerToken: os.Getenv("CYBERARK_PROVIDER_BEARER_TOKEN"),
+			OAuthApp: hconfig.OAuthApp,
+		}
+
+		if !hconfig.AuthMethod.IsNull() {
+			auth.Method = hconfig.AuthMethod.ValueString()
+		}
+
+		if hconfig.BearerToken != nil && !hconfig.BearerToken.Token.IsNull() {
+			auth.BearerToken = hconfig.BearerToken.Token.ValueString()
+		}
+
+		// Mounted secrets are only read by the auth methods that use the client secret
+		if csecFile != "" && (auth.Method == authMethodClientCredentials || auth.Method == authMethodOAuthApp) {
+			secret, err := readSecretFile(csecFile)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("clientsecret_file"),
+					"Unable to read client secret file",
+					fmt.Sprintf("Reading clientsecret_file failed: %s", err.Error()),
+				)
+				return
+			}
+
+			auth.ClientSecret = secret
+		}
+
+		if do == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("domain"),
+				"Missing CyberArk provider configuration",
+				"domain must be set in the provider block or through the CYBERARK_PROVIDER_DOMAIN environment variable, unless pvwa_url is set.",
+			)
+		}
+
+		resp.Diagnostics.Append(auth.validate()...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		client = configurePrivilegeCloud(ctx, hc, &auth, do, &resp.Diagnostics)
+	}
+
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	tflog.Info(ctx, "Configured client.")
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	data := &providerData{
+		Client: client,
+		DeletionMode: deletionMode,
+	}
+
+	resp.DataSourceData = data
+	resp.ResourceData = data
+	resp.EphemeralResourceData = data
+
+}
 
+// configurePrivilegeCloud authenticates to CyberArk Shared Services and returns the client of the Privilege Cloud domain.
+func configurePrivilegeCloud(ctx context.Context, hc *http.Client, auth *authSettings, domain string, diags *diag.Diagnostics) vaultClient {
+
+	// Tokens are refreshed by the client transport, the first one is requested now so bad credentials fail the configuration
+	tokens := newTokenManager(auth.tokenFetcher(hc))
+
+	_, err := tokens.Token(ctx)
+	if err != nil {
+		var iErr *identityError
+
+		if errors.As(err, &iErr) {
+			diags.AddError(
+				"Unable to authenticate to CyberArk Shared Services",
+				fmt.Sprintf("The identity platform for tenant [%s] rejected the %s credentials: %s\n\nHint: %s", auth.Tenant, auth.Method, err.Error(), authFailureHint(auth)),
+			)
+			return nil
+		}
+
+		diags.AddError(
+			"Unable to configure CyberArk client",
+			fmt.Sprintf("Authenticating to CyberArk Shared Services tenant [%s] with auth_method %s failed: %s\n\nHint: %s", auth.Tenant, auth.Method, err.Error(), authFailureHint(auth)),
+		)
+		return nil
+	}
+
+	return newPrivilegeCloudClient(hc, domain, tokens)
+}
+
+// configurePVWA logs on to a self-hosted PVWA and returns its client.
+func configurePVWA(ctx context.Context, hc *http.Client, pvwa *pvwaSettings, diags *diag.Diagnostics) vaultClient {
+
+	// Sessions are renewed by the client transport, the first logon happens now so bad credentials fail the configuration
+	tokens := newTokenManager(pvwa.tokenFetcher(hc))
+
+	_, err := tokens.Token(ctx)
+	if err != nil {
+		var vErr *vaultError
+
+		if errors.As(err, &vErr) {
+			diags.AddError(
+				"Unable to authenticate to PVWA",
+				fmt.Sprintf("PVWA [%s] rejected the %s logon: %s\n\nHint: %s", pvwa.URL, pvwa.AuthType, err.Error(), pvwaFailureHint(pvwa)),
+			)
+			return nil
+		}
+
+		diags.AddError(
+			"Unable to configure CyberArk client",
+			fmt.Sprintf("Logging on to PVWA [%s] with auth_type %s failed: %s\n\nHint: %s", pvwa.URL, pvwa.AuthType, err.Error(), pvwaFailureHint(pvwa)),
+		)
+		return nil
+	}
+
+	return newPVWAClient(hc, pvwa.URL, tokens)
 }
 
 // DataSources defines the data sources implemented in the provider.
 func (p *cyberarkProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource {
 		NewTokenDataSource,
+		NewAccountDataSource,
+		NewAccountSecretDataSource,
+		NewSafesDataSource,
+		NewAccountsDataSource,
 	  }
 }
 
+// EphemeralResources defines the ephemeral resources implemented in the provider.
+func (p *cyberarkProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewAccountSecretEphemeralResource,
+	}
+}
+
 // Resources defines the resources implemented in the provider.
 func (p *cyberarkProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
+		NewAccountResource,
 		NewDBAccountResource,
+		NewSSHKeyAccountResource,
+		NewWindowsAccountResource,
+		NewGCPAccountResource,
 		NewAWSAccountResource,
 		NewMSAccountResource,
 		NewSafeResource,
+		NewSafeMemberResource,
 	}
 	
 }
\ No newline at end of file
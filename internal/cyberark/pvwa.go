@@ -0,0 +1,244 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// Users defined in the vault.
+	pvwaAuthCyberArk = "cyberark"
+	// Users of an LDAP directory integrated with the vault.
+	pvwaAuthLDAP = "ldap"
+	// Users authenticated by a RADIUS server, the password is the RADIUS passcode.
+	pvwaAuthRADIUS = "radius"
+)
+
+// pvwaLogonPaths maps auth_type to the logon API of the authentication method.
+var pvwaLogonPaths = map[string]string{
+	pvwaAuthCyberArk: "auth/CyberArk/Logon",
+	pvwaAuthLDAP:     "auth/LDAP/Logon",
+	pvwaAuthRADIUS:   "auth/RADIUS/Logon",
+}
+
+type pvwaAuthModel struct {
+	AuthType htypes.String `tfsdk:"auth_type"`
+	Username htypes.String `tfsdk:"username"`
+	Password htypes.String `tfsdk:"password"`
+}
+
+// pvwaURLAttribute selects a self-hosted PVWA instead of Privilege Cloud.
+func pvwaURLAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "URL of a self-hosted PVWA, for example https://pvwa.example.com. When set the provider manages the self-hosted vault and logs on with the pvwa block instead of CyberArk Shared Services. May also be provided via CYBERARK_PROVIDER_PVWA_URL environment variable, which is ignored when tenant, domain or auth_method are set in the provider block.",
+		Optional:    true,
+		Validators: []validator.String{
+			stringvalidator.RegexMatches(regexp.MustCompile(`^https?://[^/]+`), "must be an http or https URL"),
+		},
+	}
+}
+
+// pvwaBlock holds the logon settings of a self-hosted PVWA.
+func pvwaBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "Self-hosted PVWA logon settings, used when pvwa_url is set.",
+		Attributes: map[string]schema.Attribute{
+			"auth_type": schema.StringAttribute{
+				Description: "Authentication method of the vault user: cyberark, ldap or radius. Defaults to cyberark. RADIUS passcodes are single use, the radius session is not renewed and runs must finish before the PVWA ends it.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(pvwaAuthCyberArk, pvwaAuthLDAP, pvwaAuthRADIUS),
+				},
+			},
+			"username": schema.StringAttribute{
+				Description: "Vault user used to log on to the PVWA. May also be provided via CYBERARK_PROVIDER_PVWA_USERNAME environment variable.",
+				Optional:    true,
+			},
+			"password": schema.StringAttribute{
+				Description: "Password of the vault user. May also be provided via CYBERARK_PROVIDER_PVWA_PASSWORD environment variable.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+// pvwaSettings are the provider settings used to log on to a self-hosted PVWA, after environment variable fallbacks are applied.
+type pvwaSettings struct {
+	URL      string
+	AuthType string
+	Username string
+	Password string
+}
+
+// validate reports the logon settings that are not set, on the attribute to set.
+func (s *pvwaSettings) validate() diag.Diagnostics {
+
+	var diags diag.Diagnostics
+
+	for _, setting := range []struct {
+		value string
+		name  string
+		env   string
+	}{
+		{s.Username, "username", "CYBERARK_PROVIDER_PVWA_USERNAME"},
+		{s.Password, "password", "CYBERARK_PROVIDER_PVWA_PASSWORD"},
+	} {
+		if setting.value != "" {
+			continue
+		}
+
+		diags.AddAttributeError(
+			path.Root("pvwa").AtName(setting.name),
+			"Missing CyberArk provider configuration",
+			fmt.Sprintf("pvwa.%s must be set in the provider block or through the %s environment variable when pvwa_url is set.", setting.name, setting.env),
+		)
+	}
+
+	return diags
+}
+
+// tokenFetcher returns the logon of the configured vault user, the settings must be valid.
+func (s *pvwaSettings) tokenFetcher(hc *http.Client) tokenFetcher {
+	return pvwaLogonFetcher(hc, s.URL, s.AuthType, s.Username, s.Password)
+}
+
+// pvwaLogonRequest is the body of the PVWA logon API.
+// Concurrent sessions keep parallel terraform runs with the same user from ending each other's session.
+type pvwaLogonRequest struct {
+	Username          string `json:"username"`
+	Password          string `json:"password"`
+	ConcurrentSession bool   `json:"concurrentSession"`
+}
+
+// pvwaRADIUSSessionLifetime keeps RADIUS sessions until the PVWA ends them, their passcode cannot be used for another logon.
+const pvwaRADIUSSessionLifetime = 24 * time.Hour
+
+// pvwaLogonFetcher logs on to a self-hosted PVWA and returns the session token.
+// The logon API does not report the session lifetime, the default token lifetime keeps sessions below the PVWA idle timeout.
+// The previous session is logged off before each new logon so renewals do not pile up concurrent sessions of the user.
+// RADIUS sessions are never renewed since the passcode is single use, their runs must end before the PVWA ends the session.
+func pvwaLogonFetcher(hc *http.Client, pvwaURL string, authType string, username string, password string) tokenFetcher {
+
+	var session string
+
+	return func(ctx context.Context) (string, time.Duration, error) {
+
+		if session != "" {
+			if authType == pvwaAuthRADIUS {
+				return "", 0, errors.New("the PVWA session ended and cannot be renewed, RADIUS passcodes are single use. Keep RADIUS runs shorter than the PVWA session timeout or use the cyberark or ldap auth_type")
+			}
+
+			pvwaLogoff(ctx, hc, pvwaURL, session)
+			session = ""
+		}
+
+		data, err := json.Marshal(pvwaLogonRequest{
+			Username:          username,
+			Password:          password,
+			ConcurrentSession: true,
+		})
+		if err != nil {
+			return "", 0, fmt.Errorf("unable to format logon request: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, pvwaAPIRoot(pvwaURL)+pvwaLogonPaths[authType], bytes.NewReader(data))
+		if err != nil {
+			return "", 0, fmt.Errorf("unable to construct logon request: %w", err)
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+
+		tflog.Debug(ctx, "Logging on to PVWA", map[string]interface{}{"pvwa_url": pvwaURL, "auth_type": authType, "username": username})
+
+		resp, err := hc.Do(req)
+		if err != nil {
+			return "", 0, fmt.Errorf("unable to reach PVWA: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", 0, fmt.Errorf("unable to read PVWA logon response: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			vErr := &vaultError{}
+
+			// Error bodies are best effort, keep the status code if the body cannot be decoded
+			_ = json.Unmarshal(body, vErr)
+			vErr.StatusCode = resp.StatusCode
+
+			return "", 0, vErr
+		}
+
+		var token string
+
+		if err := json.Unmarshal(body, &token); err != nil {
+			return "", 0, fmt.Errorf("unable to decode PVWA logon response: %w", err)
+		}
+
+		if token == "" {
+			return "", 0, errors.New("the PVWA did not return a session token")
+		}
+
+		session = token
+
+		if authType == pvwaAuthRADIUS {
+			return token, pvwaRADIUSSessionLifetime, nil
+		}
+
+		return token, 0, nil
+	}
+}
+
+// pvwaLogoff ends a PVWA session. Failures are only logged, the session may already have been ended by the PVWA.
+func pvwaLogoff(ctx context.Context, hc *http.Client, pvwaURL string, session string) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pvwaAPIRoot(pvwaURL)+"auth/Logoff", nil)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to construct PVWA logoff request", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	req.Header.Set("Authorization", session)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to log off from PVWA", map[string]interface{}{"error": err.Error()})
+		return
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	tflog.Debug(ctx, "Logged off from PVWA", map[string]interface{}{"pvwa_url": pvwaURL, "status": resp.StatusCode})
+}
+
+// pvwaFailureHint suggests which settings to verify when the PVWA logon fails.
+func pvwaFailureHint(s *pvwaSettings) string {
+
+	switch s.AuthType {
+	case pvwaAuthLDAP:
+		return fmt.Sprintf("Verify pvwa.username [%s] and password, and that the LDAP directory is mapped in the vault.", s.Username)
+	case pvwaAuthRADIUS:
+		return fmt.Sprintf("Verify pvwa.username [%s] and the RADIUS passcode, and that RADIUS authentication is enabled on the PVWA.", s.Username)
+	}
+
+	return fmt.Sprintf("Verify pvwa.username [%s] and password, and that the vault user is not suspended.", s.Username)
+}
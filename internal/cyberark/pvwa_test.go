@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPVWAAPIRoot(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		pvwaURL  string
+		expected string
+	}{
+		{"server", "https://pvwa.example.com", "https://pvwa.example.com/PasswordVault/API/"},
+		{"server with trailing slash", "https://pvwa.example.com/", "https://pvwa.example.com/PasswordVault/API/"},
+		{"application", "https://pvwa.example.com/PasswordVault", "https://pvwa.example.com/PasswordVault/API/"},
+		{"application with trailing slash", "https://pvwa.example.com/PasswordVault/", "https://pvwa.example.com/PasswordVault/API/"},
+		{"server with port", "http://10.0.0.5:8080", "http://10.0.0.5:8080/PasswordVault/API/"},
+		{"server behind a path", "https://gateway.example.com/cyberark", "https://gateway.example.com/cyberark/PasswordVault/API/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := pvwaAPIRoot(tt.pvwaURL); actual != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+// testPVWA is an httptest PVWA handing out numbered sessions and recording the logon and logoff calls.
+type testPVWA struct {
+	mu       sync.Mutex
+	sessions int
+	calls    []string
+}
+
+func (p *testPVWA) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/Logon"):
+		p.sessions++
+		session := fmt.Sprintf("session%d", p.sessions)
+		p.calls = append(p.calls, "logon")
+		_ = json.NewEncoder(w).Encode(session)
+	case strings.HasSuffix(r.URL.Path, "/auth/Logoff"):
+		p.calls = append(p.calls, "logoff "+r.Header.Get("Authorization"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestPVWALogonFetcherRenewal(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		authType string
+		calls    []string
+		err      bool
+	}{
+		{"previous session is logged off", pvwaAuthCyberArk, []string{"logon", "logoff session1", "logon"}, false},
+		{"ldap session is logged off", pvwaAuthLDAP, []string{"logon", "logoff session1", "logon"}, false},
+		{"radius session is not renewed", pvwaAuthRADIUS, []string{"logon"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			pvwa := &testPVWA{}
+			server := httptest.NewServer(pvwa)
+			defer server.Close()
+
+			fetch := pvwaLogonFetcher(server.Client(), server.URL, tt.authType, "admin", "password")
+
+			token, _, err := fetch(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if token != "session1" {
+				t.Errorf("expected token session1, got %s", token)
+			}
+
+			token, _, err = fetch(context.Background())
+
+			if tt.err {
+				if err == nil {
+					t.Error("expected an error")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if token != "session2" {
+					t.Errorf("expected token session2, got %s", token)
+				}
+			}
+
+			if strings.Join(pvwa.calls, ",") != strings.Join(tt.calls, ",") {
+				t.Errorf("expected calls %v, got %v", tt.calls, pvwa.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,542 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"time"
+
+	cybrtypes "github.com/aharriscybr/cybr-api/pkg/cybr/types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &accountResource{}
+	_ resource.ResourceWithConfigure = &accountResource{}
+	_ resource.ResourceWithImportState = &accountResource{}
+	_ resource.ResourceWithConfigValidators = &accountResource{}
+)
+
+// NewAccountResource is a helper function to simplify the provider implementation.
+func NewAccountResource() resource.Resource {
+	return &accountResource{}
+}
+
+// accountResource is the resource implementation.
+type accountResource struct {
+	client vaultClient
+	deletionMode string
+}
+
+// Metadata returns the resource type name.
+func (r *accountResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_account"
+}
+
+type accountCredModel struct {
+
+	Name 		htypes.String `tfsdk:"name"`
+	Address 	htypes.String `tfsdk:"address"`
+	Username 	htypes.String `tfsdk:"username"`
+	Platform 	htypes.String `tfsdk:"platform"`
+	Safe 		htypes.String `tfsdk:"safe"`
+	SecretType 	htypes.String `tfsdk:"secrettype"`
+	Secret 		htypes.String `tfsdk:"secret"`
+	ID 			htypes.String `tfsdk:"id"`
+	LastUpdated htypes.String `tfsdk:"last_updated"`
+	PlatformProps	htypes.Map `tfsdk:"platform_properties"`
+	RemoteAccess	*remoteAccessModel `tfsdk:"remote_machines_access"`
+	Manage 		htypes.Bool `tfsdk:"sm_manage"`
+	ManageReason	htypes.String `tfsdk:"sm_manage_reason"`
+	DeletionMode htypes.String `tfsdk:"deletion_mode"`
+}
+
+type remoteAccessModel struct {
+	RemoteMachines	htypes.List `tfsdk:"remote_machines"`
+	AccessRestricted	htypes.Bool `tfsdk:"access_restricted_to_remote_machines"`
+}
+
+func (r *accountResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+
+	resp.Schema = schema.Schema{
+		Description: "Generic Account Resource for onboarding accounts of any platform.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "CyberArk Privilege Cloud Credential ID- Generated from CyberArk after onboarding account into a safe.",
+				Computed: true,
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"deletion_mode": deletionModeAttribute(),
+			"name": schema.StringAttribute{
+				Description: "Custom Account Name for customizing the object name in a safe.",
+				Required: true,
+			},
+			"address": schema.StringAttribute{
+				Description: "URI, URL or IP associated with the credential.",
+				Optional: true,
+			},
+			"username": schema.StringAttribute{
+				Description: "Username of the Credential object.",
+				Required: true,
+			},
+			"platform": schema.StringAttribute{
+				Description: "Management Platform associated with the Credential. Changing the platform forces a new account.",
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"safe": schema.StringAttribute{
+				Description: "Target Safe where the credential object will be onboarded. Changing the safe forces a new account.",
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"secrettype": schema.StringAttribute{
+				Description: "Secret type of credential: password or key. Changing the secret type forces a new account.",
+				Required: true,
+				Validators: secretTypeValidators(),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"secret": schema.StringAttribute{
+				Description: "Password or key of the credential object.",
+				Required: true,
+				Sensitive: true,
+			},
+			"sm_manage": schema.BoolAttribute{
+				Description: "Automatic Management of a credential. Optional Value.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sm_manage_reason": schema.StringAttribute{
+				Description: "If sm_manage is false, provide reason why credential is not managed.",
+				Optional: true,
+			},
+			"platform_properties": schema.MapAttribute{
+				Description: "Platform account properties as defined by the platform, for example Port, LogonDomain or Database.",
+				ElementType: htypes.StringType,
+				Optional: true,
+			},
+			"remote_machines_access": schema.SingleNestedAttribute{
+				Description: "Remote machines the account can connect to through PSM.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"remote_machines": schema.ListAttribute{
+						Description: "Addresses of the remote machines the account can access.",
+						ElementType: htypes.StringType,
+						Required: true,
+					},
+					"access_restricted_to_remote_machines": schema.BoolAttribute{
+						Description: "Whether access is restricted to the listed remote machines.",
+						Optional: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+// ConfigValidators validate attribute combinations before plan.
+func (r *accountResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		manualManagementReasonValidator{},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *accountResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = data.Client
+	r.deletionMode = data.DeletionMode
+}
+
+// Create a new resource.
+func (r *accountResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var plan accountCredModel
+	var sm_props cybrtypes.SecretManagement
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var name, address, username, platform, safe, secrettype, secret, sm_manage_reason string
+
+	var sm_manage bool
+
+	name = os.Getenv("CYBERARK_ACCOUNT_CUSTOM_NAME")
+	address = os.Getenv("CYBERARK_ACCOUNT_ADDRESS")
+	username = os.Getenv("CYBERARK_ACCOUNT_USERNAME")
+	platform = os.Getenv("CYBERARK_ACCOUNT_PLATFORM")
+	safe = os.Getenv("CYBERARK_ACCOUNT_SAFE")
+	secrettype = os.Getenv("CYBERARK_ACCOUNT_SECRETTYPE")
+	secret = os.Getenv("CYBERARK_ACCOUNT_SECRET")
+
+	// Not processing env variable overrides for optional fields, this must be defined in the terraform plan.
+	// This is a design decision.
+	if !plan.Name.IsNull() {
+		name = plan.Name.ValueString()
+	}
+
+	if !plan.Address.IsNull() {
+		address = plan.Address.ValueString()
+	}
+
+	if !plan.Username.IsNull() {
+		username = plan.Username.ValueString()
+	}
+
+	if !plan.Platform.IsNull() {
+		platform = plan.Platform.ValueString()
+	}
+
+	if !plan.Safe.IsNull() {
+		safe = plan.Safe.ValueString()
+	}
+
+	if !plan.SecretType.IsNull() {
+		secrettype = plan.SecretType.ValueString()
+	}
+
+	if !plan.Secret.IsNull() {
+		secret = plan.Secret.ValueString()
+	}
+
+	if !plan.Manage.IsNull() {
+		sm_manage = plan.Manage.ValueBool()
+		sm_props.AutomaticManagement = &sm_manage
+	}
+
+	if !plan.ManageReason.IsNull() {
+		sm_manage_reason = plan.ManageReason.ValueString()
+		sm_props.ManualManagementReason = &sm_manage_reason
+	}
+
+	newAccount := vaultAccountRequest {
+		Name: &name,
+		UserName: &username,
+		Platform: &platform,
+		SafeName: &safe,
+		SecretType: &secrettype,
+		Secret: &secret,
+		SecretMgmt: &sm_props,
+	}
+
+	if address != "" {
+		newAccount.Address = &address
+	}
+
+	if !plan.PlatformProps.IsNull() {
+		resp.Diagnostics.Append(plan.PlatformProps.ElementsAs(ctx, &newAccount.PlatformProps, false)...)
+	}
+
+	if plan.RemoteAccess != nil {
+		var machines []string
+
+		resp.Diagnostics.Append(plan.RemoteAccess.RemoteMachines.ElementsAs(ctx, &machines, false)...)
+
+		newAccount.RemoteAccess = &vaultRemoteAccess {
+			RemoteMachines: strings.Join(machines, ";"),
+			AccessRestricted: plan.RemoteAccess.AccessRestricted.ValueBool(),
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	create, err := createVaultAccount(ctx, r.client, &newAccount)
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to onboard account",
+			fmt.Sprintf("Onboarding account [%s] into safe [%s] failed.", name, safe),
+			err,
+		))
+		return
+	}
+
+	plan.ID = htypes.StringValue(create)
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
+
+	// Set state to fully populated data
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Refresh Existing State
+func (r *accountResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var currState accountCredModel
+	diags := req.State.Get(ctx, &currState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var newState vaultAccount
+
+	err := vaultRequest(ctx, r.client, http.MethodGet, "Accounts/"+url.PathEscape(currState.ID.ValueString()), nil, &newState)
+	if err != nil {
+		// Objects removed outside of terraform are dropped from state so they are planned for creation
+		if isNotFound(err) {
+			tflog.Warn(ctx, "Account no longer exists in the vault, removing from state.", map[string]interface{}{"id": currState.ID.ValueString()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to read account",
+			fmt.Sprintf("Retrieving account [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
+	}
+
+	tflog.Info(ctx, "Refreshing state")
+
+	resp.Diagnostics.Append(setAccountProperties(ctx, &currState, &newState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, currState)...)
+}
+
+
+// Update patches the changed account properties and updates the secret in the vault.
+func (r *accountResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan, currState accountCredModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &currState)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := currState.ID.ValueString()
+
+	var patch vaultAccountPatch
+
+	patch.setString("/name", plan.Name, currState.Name)
+	patch.setString("/address", plan.Address, currState.Address)
+	patch.setString("/userName", plan.Username, currState.Username)
+	patch.setBool("/secretManagement/automaticManagementEnabled", plan.Manage, currState.Manage)
+	patch.setString("/secretManagement/manualManagementReason", plan.ManageReason, currState.ManageReason)
+
+	var plannedProps, currentProps map[string]string
+
+	if !plan.PlatformProps.IsNull() {
+		resp.Diagnostics.Append(plan.PlatformProps.ElementsAs(ctx, &plannedProps, false)...)
+	}
+
+	if !currState.PlatformProps.IsNull() {
+		resp.Diagnostics.Append(currState.PlatformProps.ElementsAs(ctx, &currentProps, false)...)
+	}
+
+	patch.setPlatformProperties(plannedProps, currentProps)
+
+	plannedMachines, plannedRestricted, diags := remoteAccessValues(ctx, plan.RemoteAccess)
+	resp.Diagnostics.Append(diags...)
+
+	currentMachines, currentRestricted, diags := remoteAccessValues(ctx, currState.RemoteAccess)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	patch.setString("/remoteMachinesAccess/remoteMachines", plannedMachines, currentMachines)
+	patch.setBool("/remoteMachinesAccess/accessRestrictedToRemoteMachines", plannedRestricted, currentRestricted)
+
+	if len(patch) > 0 {
+		err := patchVaultAccount(ctx, r.client, id, patch)
+		if err != nil {
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to update account",
+				fmt.Sprintf("Updating the properties of account [%s] failed.", id),
+				err,
+			))
+			return
+		}
+
+		tflog.Info(ctx, "Updated account properties", map[string]interface{}{"id": id})
+	}
+
+	plan.ID = currState.ID
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
+
+	// Secrets cannot be read back from the vault, record the configured secret after an import without updating it
+	if !currState.Secret.IsNull() && !plan.Secret.Equal(currState.Secret) {
+
+		err := updateVaultSecret(ctx, r.client, id, plan.Secret.ValueString())
+		if err != nil {
+			// Record the updated properties so only the secret is updated again
+			plan.Secret = currState.Secret
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to update account secret",
+				fmt.Sprintf("Updating the secret of account [%s] failed.", id),
+				err,
+			))
+			return
+		}
+
+		tflog.Info(ctx, "Updated account secret", map[string]interface{}{"id": id})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *accountResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var currState accountCredModel
+	diags := req.State.Get(ctx, &currState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	mode, err := resolveDeletionMode(currState.DeletionMode, r.deletionMode)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("deletion_mode"), "Invalid deletion mode", err.Error())
+		return
+	}
+
+	err = removeVaultObject(ctx, r.client, mode, "Accounts/"+url.PathEscape(currState.ID.ValueString()))
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to delete account",
+			fmt.Sprintf("Deleting account [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
+	}
+
+}
+
+// ImportState imports an existing vault account by its account ID.
+func (r *accountResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// setAccountProperties copies the account returned by the vault into the terraform model.
+// The secret is never returned by the vault and is left untouched.
+func setAccountProperties(ctx context.Context, model *accountCredModel, account *vaultAccount) diag.Diagnostics {
+
+	var diags diag.Diagnostics
+
+	// Imported accounts only have an ID in state
+	imported := model.Name.IsNull()
+
+	if account.CredID != nil {
+		model.ID = htypes.StringValue(*account.CredID)
+	}
+
+	model.Name = stringValue(account.Name)
+	model.Address = stringValue(account.Address)
+	model.Username = stringValue(account.UserName)
+	model.Platform = stringValue(account.Platform)
+	model.Safe = stringValue(account.SafeName)
+	model.SecretType = stringValue(account.SecretType)
+
+	if account.SecretMgmt != nil {
+		model.Manage = boolValue(account.SecretMgmt.AutomaticManagement)
+		model.ManageReason = stringValue(account.SecretMgmt.ManualManagementReason)
+
+		// Set last updated time to last updated time in the vault
+		if account.SecretMgmt.ModifiedTime != nil {
+			newTime := time.Unix(*account.SecretMgmt.ModifiedTime, 0)
+			model.LastUpdated = htypes.StringValue(newTime.Format(time.RFC850))
+		}
+	}
+
+	var props htypes.Map
+	var d diag.Diagnostics
+
+	if imported && len(account.PlatformProps) > 0 {
+		props, d = platformPropertiesValue(ctx, account.PlatformProps)
+	} else {
+		props, d = trackedPlatformPropertiesValue(ctx, model.PlatformProps, account.PlatformProps)
+	}
+
+	diags.Append(d...)
+	model.PlatformProps = props
+
+	if account.RemoteAccess == nil || account.RemoteAccess.RemoteMachines == "" {
+		model.RemoteAccess = nil
+		return diags
+	}
+
+	machines, d := htypes.ListValueFrom(ctx, htypes.StringType, strings.Split(account.RemoteAccess.RemoteMachines, ";"))
+	diags.Append(d...)
+
+	restricted := htypes.BoolValue(account.RemoteAccess.AccessRestricted)
+
+	// Keep an unset restriction flag unset while the vault reports the default
+	if model.RemoteAccess != nil && model.RemoteAccess.AccessRestricted.IsNull() && !account.RemoteAccess.AccessRestricted {
+		restricted = htypes.BoolNull()
+	}
+
+	model.RemoteAccess = &remoteAccessModel{
+		RemoteMachines: machines,
+		AccessRestricted: restricted,
+	}
+
+	return diags
+}
+
+// remoteAccessValues converts the remote machines access to the values patched in the vault.
+// Remote machines are null when access is not configured.
+func remoteAccessValues(ctx context.Context, m *remoteAccessModel) (htypes.String, htypes.Bool, diag.Diagnostics) {
+
+	if m == nil {
+		return htypes.StringNull(), htypes.BoolNull(), nil
+	}
+
+	var machines []string
+
+	diags := m.RemoteMachines.ElementsAs(ctx, &machines, false)
+
+	return htypes.StringValue(strings.Join(machines, ";")), m.AccessRestricted, diags
+}
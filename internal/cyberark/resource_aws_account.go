@@ -2,15 +2,23 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"net/http"
+	"net/url"
 	"os"
 	"time"
 
-	cybrapi "github.com/aharriscybr/cybr-api"
 	cybrtypes "github.com/aharriscybr/cybr-api/pkg/cybr/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	htypes "github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -19,6 +27,9 @@ import (
 var (
 	_ resource.Resource              = &awsAccountResource{}
 	_ resource.ResourceWithConfigure = &awsAccountResource{}
+	_ resource.ResourceWithImportState = &awsAccountResource{}
+	_ resource.ResourceWithConfigValidators = &awsAccountResource{}
+	_ resource.ResourceWithModifyPlan = &awsAccountResource{}
 )
 
 // NewAWSAccountResource is a helper function to simplify the provider implementation.
@@ -28,7 +39,8 @@ func NewAWSAccountResource() resource.Resource {
 
 // awsAccountResource is the resource implementation.
 type awsAccountResource struct {
-	client *cybrapi.Client
+	client vaultClient
+	deletionMode string
 }
 
 // Metadata returns the resource type name.
@@ -48,10 +60,14 @@ type awsCredModel struct {
 	Manage 		htypes.Bool `tfsdk:"sm_manage"`
 	ManageReason	htypes.String `tfsdk:"sm_manage_reason"`
 	AWSKID 		htypes.String `tfsdk:"aws_kid"`
+	AccessKeyID 		htypes.String `tfsdk:"access_key_id"`
+	SecretAccessKey 		htypes.String `tfsdk:"secret_access_key"`
+	AccessKey 		htypes.Dynamic `tfsdk:"access_key"`
 	AWSAccount 		htypes.String `tfsdk:"aws_accountid"`
 	Alias 		htypes.String `tfsdk:"aws_alias"`
 	Region 		htypes.String `tfsdk:"aws_accountregion"`
 
+	DeletionMode htypes.String `tfsdk:"deletion_mode"`
 }
 
 func (r *awsAccountResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -65,34 +81,70 @@ func (r *awsAccountResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"last_updated": schema.StringAttribute{
 				Computed: true,
 			},
+			"deletion_mode": deletionModeAttribute(),
 			"name": schema.StringAttribute{
 				Description: "Custom Account Name for customizing the object name in a safe.",
 				Required: true,
 			},
 			"username": schema.StringAttribute{
-				Description: "Username of the Credential object.",
-				Required: true,
+				Description: "Username of the Credential object, usually the IAM user name, for example aws_iam_access_key.example.user. Defaults to the user of access_key.",
+				Optional: true,
+				Computed: true,
 			},
 			"platform": schema.StringAttribute{
-				Description: "Management Platform associated with the Database Credential.",
+				Description: "Management Platform associated with the AWS Credential. Changing the platform forces a new account.",
 				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"safe": schema.StringAttribute{
-				Description: "Target Safe where the credential object will be onboarded.",
+				Description: "Target Safe where the credential object will be onboarded. Changing the safe forces a new account.",
 				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"secrettype": schema.StringAttribute{
-				Description: "Secret type of credentials, for AWS Accounts this value must be set to key.",
-				Required: true,
+				Description: "Secret type of credentials, for AWS Accounts this value must be set to key. Defaults to key. Changing the secret type forces a new account.",
+				Optional: true,
+				Computed: true,
+				Default: stringdefault.StaticString("key"),
+				Validators: secretTypeValidators(),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"secret": schema.StringAttribute{
 				Description: "Secret Key of the credential object.",
-				Required: true,
+				DeprecationMessage: "Use secret_access_key instead.",
+				Optional: true,
+				Sensitive: true,
+			},
+			"access_key_id": schema.StringAttribute{
+				Description: "AWS Access Key ID of the access key pair, for example aws_iam_access_key.example.id. Defaults to the id of access_key.",
+				Optional: true,
+				Computed: true,
+				Validators: awsAccessKeyIDValidators(),
+			},
+			"secret_access_key": schema.StringAttribute{
+				Description: "AWS Secret Access Key of the access key pair, for example aws_iam_access_key.example.secret. Defaults to the secret of access_key.",
+				Optional: true,
+				Computed: true,
+				Sensitive: true,
+			},
+			"access_key": schema.DynamicAttribute{
+				Description: "Access key pair shaped like an aws_iam_access_key resource, for example access_key = aws_iam_access_key.example. The id, secret and user attributes are used, any other attribute is ignored. Conflicts with access_key_id, secret_access_key, aws_kid and secret.",
+				Optional: true,
 				Sensitive: true,
 			},
 			"sm_manage": schema.BoolAttribute{
 				Description: "Automatic Management of a credential. Optional Value.",
 				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"sm_manage_reason": schema.StringAttribute{
 				Description: "If sm_manage is false, provide reason why credential is not managed.",
@@ -100,11 +152,14 @@ func (r *awsAccountResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			},
 			"aws_kid": schema.StringAttribute{
 				Description: "AWS Access Key ID.",
-				Required: true,
+				DeprecationMessage: "Use access_key_id instead.",
+				Optional: true,
+				Validators: awsAccessKeyIDValidators(),
 			},
 			"aws_accountid": schema.StringAttribute{
-				Description: "AWS Account ID Number.",
+				Description: "AWS Account ID Number, 12 digits.",
 				Required: true,
+				Validators: awsAccountIDValidators(),
 			},
 			"aws_alias": schema.StringAttribute{
 				Description: "AWS Account Alias.",
@@ -113,29 +168,106 @@ func (r *awsAccountResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"aws_accountregion": schema.StringAttribute{
 				Description: "AWS Region.",
 				Optional: true,
+				Validators: awsRegionValidators(),
 			},
 		},
 	}
 }
 
+// ConfigValidators validate attribute combinations before plan.
+func (r *awsAccountResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		manualManagementReasonValidator{},
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("access_key_id"),
+			path.MatchRoot("aws_kid"),
+			path.MatchRoot("access_key"),
+		),
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("secret_access_key"),
+			path.MatchRoot("secret"),
+			path.MatchRoot("access_key"),
+		),
+		resourcevalidator.AtLeastOneOf(
+			path.MatchRoot("username"),
+			path.MatchRoot("access_key"),
+		),
+	}
+}
+
+// ModifyPlan resolves the access key pair and username from whichever attributes configure them.
+func (r *awsAccountResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+
+	// Nothing to plan when the resource is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var config awsCredModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	keyID, secret, username := config.AccessKeyID, config.SecretAccessKey, config.Username
+
+	if !config.AWSKID.IsNull() {
+		keyID = config.AWSKID
+	}
+
+	if !config.Secret.IsNull() {
+		secret = config.Secret
+	}
+
+	if !config.AccessKey.IsNull() {
+		pair, err := awsAccessKeyPair(config.AccessKey)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("access_key"), "Invalid access key", err.Error())
+			return
+		}
+
+		keyID, secret = pair.ID, pair.Secret
+
+		if username.IsNull() {
+			if pair.User.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root("username"), "Missing username", "username must be set when access_key has no user attribute.")
+				return
+			}
+
+			username = pair.User
+		}
+	}
+
+	// Missing attributes are reported by the config validators
+	if keyID.IsNull() || secret.IsNull() || username.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("access_key_id"), keyID)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("secret_access_key"), secret)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("username"), username)...)
+}
+
 // Configure adds the provider configured client to the resource.
 func (r *awsAccountResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*cybrapi.Client)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *cybrapi.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = data.Client
+	r.deletionMode = data.DeletionMode
 }
 
 // Create a new resource.
@@ -145,7 +277,7 @@ func (r *awsAccountResource) Create(ctx context.Context, req resource.CreateRequ
 	var props cybrtypes.AccountProps
 	var sm_props cybrtypes.SecretManagement
 
-	resp.Diagnostics.Append(req.Config.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -191,11 +323,11 @@ func (r *awsAccountResource) Create(ctx context.Context, req resource.CreateRequ
 		secrettype = plan.SecretType.ValueString()
 	}
 
-	if !plan.Secret.IsNull() {
-		secret = plan.Secret.ValueString()
+	if !plan.SecretAccessKey.IsNull() {
+		secret = plan.SecretAccessKey.ValueString()
 	}
 	
-	if !plan.Manage.IsNull() {
+	if !plan.Manage.IsNull() && !plan.Manage.IsUnknown() {
 		sm_manage = plan.Manage.ValueBool()
 		sm_props.AutomaticManagement = &sm_manage
 	}
@@ -205,8 +337,8 @@ func (r *awsAccountResource) Create(ctx context.Context, req resource.CreateRequ
 		sm_props.ManualManagementReason = &sm_manage_reason
 	}
 
-	if !plan.AWSKID.IsNull() {
-		aws_kid = plan.AWSKID.ValueString()
+	if !plan.AccessKeyID.IsNull() {
+		aws_kid = plan.AccessKeyID.ValueString()
 		props.AWSKID = &aws_kid
 	}
 
@@ -238,25 +370,25 @@ func (r *awsAccountResource) Create(ctx context.Context, req resource.CreateRequ
 
 
 
-	create, err := cybrapi.CreateAccount(&newAccount, r.client.AuthToken, r.client.Domain)
+	create, err := createVaultAccount(ctx, r.client, &newAccount)
 	if err != nil {
-		log.Fatal(err)
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to onboard account",
+			fmt.Sprintf("Onboarding account [%s] into safe [%s] failed.", name, safe),
+			err,
+		))
+		return
 	}
 
-	if create == "" || len(create) == 0 {
-
-		tflog.Error(ctx, "Error catching ID, this means provisioning failed in pipeline. Please check debug logs and try again.")
-
-	} else {
-
-		plan.ID = htypes.StringValue(create)
-		plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
-	
-		// Set state to fully populated data
-		resp.State.Set(ctx, plan)
+	plan.ID = htypes.StringValue(create)
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
 
+	if plan.Manage.IsUnknown() {
+		plan.Manage = htypes.BoolNull()
 	}
-	
+
+	// Set state to fully populated data
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
 // Refresh Existing State
@@ -269,42 +401,254 @@ func (r *awsAccountResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	newState, err := cybrapi.GetAccount(currState.ID.ValueStringPointer(), r.client.AuthToken, r.client.Domain)
+	var newState cybrtypes.CredentialResponse
+
+	err := vaultRequest(ctx, r.client, http.MethodGet, "Accounts/"+url.PathEscape(currState.ID.ValueString()), nil, &newState)
 	if err != nil {
-		tflog.Error(ctx, "Unable to retrieve state from CyberArk API")
+		// Objects removed outside of terraform are dropped from state so they are planned for creation
+		if isNotFound(err) {
+			tflog.Warn(ctx, "Account no longer exists in the vault, removing from state.", map[string]interface{}{"id": currState.ID.ValueString()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to read account",
+			fmt.Sprintf("Retrieving account [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
 	}
 
 	tflog.Info(ctx, "Refreshing state")
 
-	// Main Credentials
-	currState.Name = htypes.StringValue(*newState.Name)
-	currState.Platform = htypes.StringValue(*newState.Platform)
-	currState.Safe = htypes.StringValue(*newState.SafeName)
-	currState.Username = htypes.StringValue(*newState.UserName)
-	currState.SecretType = htypes.StringValue(*newState.SecretType)
-	
-	// AWS Props
-	currState.AWSKID = htypes.StringValue(*newState.Props.AWSKID)
-	currState.AWSAccount = htypes.StringValue(*newState.Props.AWSAccount)
-	currState.Alias = htypes.StringValue(*newState.Props.Alias)
-	currState.Region = htypes.StringValue(*newState.Props.Region)
-	
-	// Set last updated time to last updated tim in the vault
-	newTime := time.Unix(*newState.SecretMgmt.ModifiedTime, 0)
-	currState.LastUpdated = htypes.StringValue(newTime.Format(time.RFC850))
-
-	// Ensure ID is consistent
-	currState.ID = htypes.StringValue(*newState.CredID)
+	setAWSAccountProperties(&currState, &newState)
 
+	resp.Diagnostics.Append(resp.State.Set(ctx, currState)...)
 }
 
 
-// Update updates the resource and sets the updated Terraform state on success.
+// Update patches the changed account and AWS properties and uploads a rotated access key pair to the vault.
 func (r *awsAccountResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	tflog.Info(ctx, "Update is not supported through terraform. Please consult with your CyberArk Administrator to process account property updates.")
+
+	var plan, currState awsCredModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &currState)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := currState.ID.ValueString()
+
+	// State written before secret_access_key existed only holds the deprecated secret
+	currentSecret := currState.SecretAccessKey
+	if currentSecret.IsNull() {
+		currentSecret = currState.Secret
+	}
+
+	var patch vaultAccountPatch
+
+	patch.setString("/name", plan.Name, currState.Name)
+	patch.setString("/userName", plan.Username, currState.Username)
+	patch.setBool("/secretManagement/automaticManagementEnabled", plan.Manage, currState.Manage)
+	patch.setString("/secretManagement/manualManagementReason", plan.ManageReason, currState.ManageReason)
+	patch.setString("/platformAccountProperties/AWSAccountID", plan.AWSAccount, currState.AWSAccount)
+	patch.setString("/platformAccountProperties/AWSAccountAliasName", plan.Alias, currState.Alias)
+	patch.setString("/platformAccountProperties/Region", plan.Region, currState.Region)
+	patch.setString("/platformAccountProperties/AWSAccessKeyID", plan.AccessKeyID, currState.AccessKeyID)
+
+	// Secrets cannot be read back from the vault, record the configured key pair after an import without uploading it
+	secretChanged := !currentSecret.IsNull() && !plan.SecretAccessKey.Equal(currentSecret)
+
+	if len(patch) > 0 {
+		err := patchVaultAccount(ctx, r.client, id, patch)
+		if err != nil {
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to update account",
+				fmt.Sprintf("Updating the properties of account [%s] failed.", id),
+				err,
+			))
+			return
+		}
+
+		tflog.Info(ctx, "Updated account properties", map[string]interface{}{"id": id})
+	}
+
+	plan.ID = currState.ID
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
+
+	if plan.Manage.IsUnknown() {
+		plan.Manage = currState.Manage
+	}
+
+	if secretChanged {
+
+		err := updateVaultSecret(ctx, r.client, id, plan.SecretAccessKey.ValueString())
+		if err != nil {
+			// Record the updated properties so only the secret access key is uploaded again
+			plan.Secret = currState.Secret
+			plan.SecretAccessKey = currState.SecretAccessKey
+			plan.AccessKey = currState.AccessKey
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to update access key",
+				fmt.Sprintf("Uploading the rotated secret access key of account [%s] failed.", id),
+				err,
+			))
+			return
+		}
+
+		tflog.Info(ctx, "Updated account access key pair", map[string]interface{}{"id": id, "access_key_id": plan.AccessKeyID.ValueString()})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *awsAccountResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	tflog.Info(ctx, "Delete is not supported through terraform. Please consult with your CyberArk Administrator to process deleting this resource.")
-}
\ No newline at end of file
+
+	var currState awsCredModel
+	diags := req.State.Get(ctx, &currState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	mode, err := resolveDeletionMode(currState.DeletionMode, r.deletionMode)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("deletion_mode"), "Invalid deletion mode", err.Error())
+		return
+	}
+
+	err = removeVaultObject(ctx, r.client, mode, "Accounts/"+url.PathEscape(currState.ID.ValueString()))
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to delete account",
+			fmt.Sprintf("Deleting account [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
+	}
+
+}
+
+// ImportState imports an existing vault account by its account ID.
+func (r *awsAccountResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// setAWSAccountProperties copies the AWS account returned by the vault into the terraform model.
+// The secret is never returned by the vault and is left untouched.
+func setAWSAccountProperties(model *awsCredModel, account *cybrtypes.CredentialResponse) {
+
+	if account.CredID != nil {
+		model.ID = htypes.StringValue(*account.CredID)
+	}
+
+	model.Name = stringValue(account.Name)
+	model.Username = stringValue(account.UserName)
+	model.Platform = stringValue(account.Platform)
+	model.Safe = stringValue(account.SafeName)
+	model.SecretType = stringValue(account.SecretType)
+
+	if account.SecretMgmt != nil {
+		model.Manage = boolValue(account.SecretMgmt.AutomaticManagement)
+		model.ManageReason = stringValue(account.SecretMgmt.ManualManagementReason)
+
+		// Set last updated time to last updated time in the vault
+		if account.SecretMgmt.ModifiedTime != nil {
+			newTime := time.Unix(*account.SecretMgmt.ModifiedTime, 0)
+			model.LastUpdated = htypes.StringValue(newTime.Format(time.RFC850))
+		}
+	}
+
+	if account.Props == nil {
+		return
+	}
+
+	// AWS Props
+	model.AccessKeyID = stringValue(account.Props.AWSKID)
+	model.AWSAccount = stringValue(account.Props.AWSAccount)
+	model.Alias = stringValue(account.Props.Alias)
+	model.Region = stringValue(account.Props.Region)
+
+	// Only refresh the deprecated key ID while it is still configured
+	if !model.AWSKID.IsNull() {
+		model.AWSKID = model.AccessKeyID
+	}
+}
+
+// awsKeyPair is an access key pair read from an aws_iam_access_key shaped object.
+type awsKeyPair struct {
+	ID     htypes.String
+	Secret htypes.String
+	User   htypes.String
+}
+
+// awsAccessKeyPair reads the id, secret and user attributes of an aws_iam_access_key shaped object.
+// Values only known at apply time are returned as unknown.
+func awsAccessKeyPair(value htypes.Dynamic) (awsKeyPair, error) {
+
+	if value.IsUnknown() || value.IsUnderlyingValueUnknown() {
+		return awsKeyPair{htypes.StringUnknown(), htypes.StringUnknown(), htypes.StringUnknown()}, nil
+	}
+
+	if value.IsUnderlyingValueNull() {
+		return awsKeyPair{}, errors.New("access_key has no id attribute")
+	}
+
+	var attributes map[string]attr.Value
+
+	switch v := value.UnderlyingValue().(type) {
+	case htypes.Object:
+		attributes = v.Attributes()
+	case htypes.Map:
+		attributes = v.Elements()
+	default:
+		return awsKeyPair{}, errors.New("access_key must be an aws_iam_access_key resource or an object with id and secret attributes")
+	}
+
+	var pair awsKeyPair
+	var err error
+
+	if pair.ID, err = awsKeyPairAttribute(attributes, "id", true); err != nil {
+		return awsKeyPair{}, err
+	}
+
+	if pair.Secret, err = awsKeyPairAttribute(attributes, "secret", true); err != nil {
+		return awsKeyPair{}, err
+	}
+
+	if pair.User, err = awsKeyPairAttribute(attributes, "user", false); err != nil {
+		return awsKeyPair{}, err
+	}
+
+	return pair, nil
+}
+
+// awsKeyPairAttribute returns a string attribute of an access key object.
+func awsKeyPairAttribute(attributes map[string]attr.Value, name string, required bool) (htypes.String, error) {
+
+	v, ok := attributes[name]
+	if !ok || v.IsNull() {
+		if required {
+			return htypes.StringNull(), fmt.Errorf("access_key has no %s attribute", name)
+		}
+
+		return htypes.StringNull(), nil
+	}
+
+	if v.IsUnknown() {
+		return htypes.StringUnknown(), nil
+	}
+
+	s, ok := v.(htypes.String)
+	if !ok {
+		return htypes.StringNull(), fmt.Errorf("the %s attribute of access_key must be a string", name)
+	}
+
+	return s, nil
+}
@@ -3,15 +3,23 @@ package provider
 import (
 	"context"
 	"fmt"
-	"log"
+	"net/http"
+	"net/url"
 	"os"
+	"regexp"
 
 	"time"
 
-	cybrapi "github.com/aharriscybr/cybr-api"
 	cybrtypes "github.com/aharriscybr/cybr-api/pkg/cybr/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	htypes "github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -20,6 +28,9 @@ import (
 var (
 	_ resource.Resource              = &dbAccountResource{}
 	_ resource.ResourceWithConfigure = &dbAccountResource{}
+	_ resource.ResourceWithImportState = &dbAccountResource{}
+	_ resource.ResourceWithConfigValidators = &dbAccountResource{}
+	_ resource.ResourceWithModifyPlan = &dbAccountResource{}
 )
 
 // NewDBAccountResource is a helper function to simplify the provider implementation.
@@ -29,7 +40,8 @@ func NewDBAccountResource() resource.Resource {
 
 // dbAccountResource is the resource implementation.
 type dbAccountResource struct {
-	client *cybrapi.Client
+	client vaultClient
+	deletionMode string
 }
 
 // Metadata returns the resource type name.
@@ -37,6 +49,58 @@ func (r *dbAccountResource) Metadata(_ context.Context, req resource.MetadataReq
 	resp.TypeName = req.ProviderTypeName + "_dbaccount"
 }
 
+// Database engines with typed connection options.
+const (
+	dbEngineMSSQL      = "mssql"
+	dbEngineOracle     = "oracle"
+	dbEnginePostgreSQL = "postgresql"
+	dbEngineMySQL      = "mysql"
+	dbEngineMongoDB    = "mongodb"
+)
+
+// dbEnginePorts are the default ports of each database engine, used when db_port is not set.
+var dbEnginePorts = map[string]string{
+	dbEngineMSSQL:      "1433",
+	dbEngineOracle:     "1521",
+	dbEnginePostgreSQL: "5432",
+	dbEngineMySQL:      "3306",
+	dbEngineMongoDB:    "27017",
+}
+
+// dbEngineNames returns the supported database engines in a stable order.
+func dbEngineNames() []string {
+	return []string{dbEngineMSSQL, dbEngineOracle, dbEnginePostgreSQL, dbEngineMySQL, dbEngineMongoDB}
+}
+
+// Platform account properties of the database platforms.
+const (
+	dbPropertyPort         = "port"
+	dbPropertyDatabase     = "database"
+	dbPropertyDSN          = "dsn"
+	dbPropertyInstance     = "Instance"
+	dbPropertySSLMode      = "SSLMode"
+	dbPropertyAuthDatabase = "AuthenticationDatabase"
+	dbPropertyReplicaSet   = "ReplicaSet"
+)
+
+type dbMSSQLModel struct {
+	Instance	htypes.String `tfsdk:"instance"`
+}
+
+type dbOracleModel struct {
+	ServiceName	htypes.String `tfsdk:"service_name"`
+	SID			htypes.String `tfsdk:"sid"`
+}
+
+type dbSSLModeModel struct {
+	SSLMode		htypes.String `tfsdk:"ssl_mode"`
+}
+
+type dbMongoDBModel struct {
+	AuthDatabase	htypes.String `tfsdk:"auth_database"`
+	ReplicaSet	htypes.String `tfsdk:"replica_set"`
+}
+
 type dbCredModel struct {
 
 	Name 		htypes.String `tfsdk:"name"`
@@ -51,9 +115,16 @@ type dbCredModel struct {
 	DBPort 		htypes.String `tfsdk:"db_port"`
 	DBName 		htypes.String `tfsdk:"dbname"`
 	DBDSN 		htypes.String `tfsdk:"db_dsn"`
+	Engine 		htypes.String `tfsdk:"engine"`
+	MSSQL 		*dbMSSQLModel `tfsdk:"mssql"`
+	Oracle 		*dbOracleModel `tfsdk:"oracle"`
+	PostgreSQL 		*dbSSLModeModel `tfsdk:"postgresql"`
+	MySQL 		*dbSSLModeModel `tfsdk:"mysql"`
+	MongoDB 		*dbMongoDBModel `tfsdk:"mongodb"`
 
 	Manage 		htypes.Bool `tfsdk:"sm_manage"`
 	ManageReason	htypes.String `tfsdk:"sm_manage_reason"`
+	DeletionMode htypes.String `tfsdk:"deletion_mode"`
 }
 
 func (r *dbAccountResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -68,6 +139,7 @@ func (r *dbAccountResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"last_updated": schema.StringAttribute{
 				Computed: true,
 			},
+			"deletion_mode": deletionModeAttribute(),
 			"name": schema.StringAttribute{
 				Description: "Custom Account Name for customizing the object name in a safe.",
 				Required: true,
@@ -81,16 +153,26 @@ func (r *dbAccountResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Required: true,
 			},
 			"platform": schema.StringAttribute{
-				Description: "Management Platform associated with the Database Credential.",
+				Description: "Management Platform associated with the Database Credential. Changing the platform forces a new account.",
 				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"safe": schema.StringAttribute{
-				Description: "Target Safe where the credential object will be onboarded.",
+				Description: "Target Safe where the credential object will be onboarded. Changing the safe forces a new account.",
 				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"secrettype": schema.StringAttribute{
-				Description: "Secret type of credential, should always be password unless working with AWS Keys.",
+				Description: "Secret type of credential, should always be password unless working with AWS or SSH keys. Changing the secret type forces a new account.",
 				Required: true,
+				Validators: secretTypeValidators(),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"secret": schema.StringAttribute{
 				Description: "Password of the credential object.",
@@ -100,27 +182,156 @@ func (r *dbAccountResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"sm_manage": schema.BoolAttribute{
 				Description: "Automatic Management of a credential. Optional Value.",
 				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"sm_manage_reason": schema.StringAttribute{
 				Description: "If sm_manage is false, provide reason why credential is not managed.",
 				Optional: true,
 			},
 			"db_port": schema.StringAttribute{
-				Description: "Database connection port.",
+				Description: "Database connection port. Defaults to the standard port of engine: mssql 1433, oracle 1521, postgresql 5432, mysql 3306 and mongodb 27017.",
 				Optional: true,
+				Computed: true,
+				Validators: portValidators(),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"dbname": schema.StringAttribute{
-				Description: "Database name.",
+				Description: "Database name. Oracle databases are identified with oracle.service_name or oracle.sid instead.",
 				Optional: true,
 			},
 			"db_dsn": schema.StringAttribute{
-				Description: "Database data source name.",
+				Description: "Database data source name, only needed for connection options that have no typed attribute.",
+				Optional: true,
+			},
+			"engine": schema.StringAttribute{
+				Description: "Database engine, one of mssql, oracle, postgresql, mysql or mongodb. Selects the default port and which engine options can be set.",
 				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(dbEngineNames()...),
+				},
+			},
+			"mssql": schema.SingleNestedAttribute{
+				Description: "Microsoft SQL Server options, requires engine mssql.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"instance": schema.StringAttribute{
+						Description: "Named instance to connect to.",
+						Optional: true,
+					},
+				},
+			},
+			"oracle": schema.SingleNestedAttribute{
+				Description: "Oracle options, requires engine oracle. Exactly one of service_name or sid must be set.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"service_name": schema.StringAttribute{
+						Description: "Service name of the database.",
+						Optional: true,
+						Validators: []validator.String{
+							stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("sid")),
+						},
+					},
+					"sid": schema.StringAttribute{
+						Description: "SID of the database instance, onboarded as a connect descriptor in the dsn property.",
+						Optional: true,
+					},
+				},
+			},
+			"postgresql": schema.SingleNestedAttribute{
+				Description: "PostgreSQL options, requires engine postgresql.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"ssl_mode": schema.StringAttribute{
+						Description: "SSL mode of the connection, one of disable, allow, prefer, require, verify-ca or verify-full.",
+						Optional: true,
+						Validators: []validator.String{
+							stringvalidator.OneOf("disable", "allow", "prefer", "require", "verify-ca", "verify-full"),
+						},
+					},
+				},
+			},
+			"mysql": schema.SingleNestedAttribute{
+				Description: "MySQL options, requires engine mysql.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"ssl_mode": schema.StringAttribute{
+						Description: "SSL mode of the connection, one of DISABLED, PREFERRED, REQUIRED, VERIFY_CA or VERIFY_IDENTITY.",
+						Optional: true,
+						Validators: []validator.String{
+							stringvalidator.OneOf("DISABLED", "PREFERRED", "REQUIRED", "VERIFY_CA", "VERIFY_IDENTITY"),
+						},
+					},
+				},
+			},
+			"mongodb": schema.SingleNestedAttribute{
+				Description: "MongoDB options, requires engine mongodb.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"auth_database": schema.StringAttribute{
+						Description: "Database the account authenticates against. Defaults to admin.",
+						Optional: true,
+						Computed: true,
+						Default: stringdefault.StaticString("admin"),
+					},
+					"replica_set": schema.StringAttribute{
+						Description: "Name of the replica set to connect to.",
+						Optional: true,
+					},
+				},
 			},
 		},
 	}
 }
 
+// ConfigValidators validate attribute combinations before plan.
+func (r *dbAccountResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		manualManagementReasonValidator{},
+		dbEngineValidator{},
+	}
+}
+
+// ModifyPlan defaults the port to the standard port of the configured engine.
+func (r *dbAccountResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+
+	// Nothing to plan when the resource is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var engine, port htypes.String
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("engine"), &engine)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("db_port"), &port)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !port.IsNull() || engine.IsUnknown() {
+		return
+	}
+
+	// Without an engine there is no default port, it is left to the platform
+	if engine.IsNull() {
+		var planned htypes.String
+
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("db_port"), &planned)...)
+
+		if planned.IsUnknown() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("db_port"), htypes.StringNull())...)
+		}
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("db_port"), dbEnginePorts[engine.ValueString()])...)
+}
+
 // Configure adds the provider configured client to the resource.
 func (r *dbAccountResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 
@@ -128,36 +339,36 @@ func (r *dbAccountResource) Configure(_ context.Context, req resource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*cybrapi.Client)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *cybrapi.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = data.Client
+	r.deletionMode = data.DeletionMode
 }
 
 // Create a new resource.
 func (r *dbAccountResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 
 	var plan dbCredModel
-	var props cybrtypes.AccountProps
 	var sm_props cybrtypes.SecretManagement
 
 
-	resp.Diagnostics.Append(req.Config.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	var name, address, username, platform, safe, secrettype, secret, db_port, dbname, db_dsn, sm_manage_reason string
+	var name, address, username, platform, safe, secrettype, secret, sm_manage_reason string
 
 	var sm_manage bool
 
@@ -203,22 +414,11 @@ func (r *dbAccountResource) Create(ctx context.Context, req resource.CreateReque
 		secret = plan.Secret.ValueString()
 	}
 
-	if !plan.DBPort.IsNull() {
-		db_port = plan.DBPort.ValueString()
-		props.Port = &db_port
+	if plan.DBPort.IsUnknown() {
+		plan.DBPort = htypes.StringNull()
 	}
 
-	if !plan.DBName.IsNull() {
-		dbname = plan.DBName.ValueString()
-		props.DBName = &dbname
-	}
-
-	if !plan.DBDSN.IsNull() {
-		db_dsn = plan.DBDSN.ValueString()
-		props.DSN = &db_dsn
-	}
-
-	if !plan.Manage.IsNull() {
+	if !plan.Manage.IsNull() && !plan.Manage.IsUnknown() {
 		sm_manage = plan.Manage.ValueBool()
 		sm_props.AutomaticManagement = &sm_manage
 	}
@@ -229,7 +429,7 @@ func (r *dbAccountResource) Create(ctx context.Context, req resource.CreateReque
 	}
 
 
-	newAccount := cybrtypes.Credential {
+	newAccount := vaultAccountRequest {
 		Name: &name,
 		Address: &address,
 		UserName: &username,
@@ -237,29 +437,29 @@ func (r *dbAccountResource) Create(ctx context.Context, req resource.CreateReque
 		SafeName: &safe,
 		SecretType: &secrettype,
 		Secret: &secret,
-		Props: &props,
 		SecretMgmt: &sm_props,
+		PlatformProps: plan.platformProperties(address),
 	}
 
-	create, err := cybrapi.CreateAccount(&newAccount, r.client.AuthToken, r.client.Domain)
+	create, err := createVaultAccount(ctx, r.client, &newAccount)
 	if err != nil {
-		log.Fatal(err)
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to onboard account",
+			fmt.Sprintf("Onboarding account [%s] into safe [%s] failed.", name, safe),
+			err,
+		))
+		return
 	}
 
-	if create == "" || len(create) == 0 {
-
-		tflog.Error(ctx, "Error catching ID, this means provisioning failed in pipeline. Please check debug logs and try again.")
-
-	} else {
-
-		plan.ID = htypes.StringValue(create)
-		plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
-	
-		// Set state to fully populated data
-		resp.State.Set(ctx, plan)
+	plan.ID = htypes.StringValue(create)
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
 
+	if plan.Manage.IsUnknown() {
+		plan.Manage = htypes.BoolNull()
 	}
-	
+
+	// Set state to fully populated data
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
 // Refresh Existing State
@@ -272,43 +472,268 @@ func (r *dbAccountResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	newState, err := cybrapi.GetAccount(currState.ID.ValueStringPointer(), r.client.AuthToken, r.client.Domain)
+	var newState vaultAccount
+
+	err := vaultRequest(ctx, r.client, http.MethodGet, "Accounts/"+url.PathEscape(currState.ID.ValueString()), nil, &newState)
 	if err != nil {
-		tflog.Error(ctx, "Unable to retrieve state from CyberArk API")
+		// Objects removed outside of terraform are dropped from state so they are planned for creation
+		if isNotFound(err) {
+			tflog.Warn(ctx, "Account no longer exists in the vault, removing from state.", map[string]interface{}{"id": currState.ID.ValueString()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to read account",
+			fmt.Sprintf("Retrieving account [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
 	}
 
 	tflog.Info(ctx, "Refreshing state")
 
-	// Main Credentials
-	currState.Name = htypes.StringValue(*newState.Name)
-	
-	currState.Address = htypes.StringValue(*newState.Address)
-	currState.Platform = htypes.StringValue(*newState.Platform)
-	currState.Safe = htypes.StringValue(*newState.SafeName)
-	currState.Username = htypes.StringValue(*newState.UserName)
-	currState.SecretType = htypes.StringValue(*newState.SecretType)
-	
-	// DB Props
-	currState.DBDSN = htypes.StringValue(*newState.Props.DSN)
-	currState.DBPort = htypes.StringValue(*newState.Props.Port)
-	currState.DBName = htypes.StringValue(*newState.Props.DBName)
-	
-	// Set last updated time to last updated tim in the vault
-	newTime := time.Unix(*newState.SecretMgmt.ModifiedTime, 0)
-	currState.LastUpdated = htypes.StringValue(newTime.Format(time.RFC850))
-
-	// Ensure ID is consistent
-	currState.ID = htypes.StringValue(*newState.CredID)
+	setDBAccountProperties(&currState, &newState)
 
+	resp.Diagnostics.Append(resp.State.Set(ctx, currState)...)
 }
 
 
-// Update updates the resource and sets the updated Terraform state on success.
+// Update patches the changed account properties and database options and updates the secret in the vault.
 func (r *dbAccountResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	tflog.Info(ctx, "Update is not supported through terraform. Please consult with your CyberArk Administrator to process account property updates.")
+
+	var plan, currState dbCredModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &currState)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := currState.ID.ValueString()
+
+	var patch vaultAccountPatch
+
+	patch.setString("/name", plan.Name, currState.Name)
+	patch.setString("/address", plan.Address, currState.Address)
+	patch.setString("/userName", plan.Username, currState.Username)
+	patch.setBool("/secretManagement/automaticManagementEnabled", plan.Manage, currState.Manage)
+	patch.setString("/secretManagement/manualManagementReason", plan.ManageReason, currState.ManageReason)
+
+	// Engine options are stored as platform account properties, a changed engine shows up as changed properties
+	patch.setPlatformProperties(plan.platformProperties(plan.Address.ValueString()), currState.platformProperties(currState.Address.ValueString()))
+
+	if len(patch) > 0 {
+		err := patchVaultAccount(ctx, r.client, id, patch)
+		if err != nil {
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to update account",
+				fmt.Sprintf("Updating the properties of account [%s] failed.", id),
+				err,
+			))
+			return
+		}
+
+		tflog.Info(ctx, "Updated account properties", map[string]interface{}{"id": id})
+	}
+
+	plan.ID = currState.ID
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
+
+	if plan.Manage.IsUnknown() {
+		plan.Manage = currState.Manage
+	}
+
+	if plan.DBPort.IsUnknown() {
+		plan.DBPort = currState.DBPort
+	}
+
+	// Secrets cannot be read back from the vault, record the configured secret after an import without updating it
+	if !currState.Secret.IsNull() && !plan.Secret.Equal(currState.Secret) {
+
+		err := updateVaultSecret(ctx, r.client, id, plan.Secret.ValueString())
+		if err != nil {
+			// Record the updated properties so only the secret is updated again
+			plan.Secret = currState.Secret
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to update account secret",
+				fmt.Sprintf("Updating the secret of account [%s] failed.", id),
+				err,
+			))
+			return
+		}
+
+		tflog.Info(ctx, "Updated account secret", map[string]interface{}{"id": id})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *dbAccountResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	tflog.Info(ctx, "Delete is not supported through terraform. Please consult with your CyberArk Administrator to process deleting this resource.")
-}
\ No newline at end of file
+
+	var currState dbCredModel
+	diags := req.State.Get(ctx, &currState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	mode, err := resolveDeletionMode(currState.DeletionMode, r.deletionMode)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("deletion_mode"), "Invalid deletion mode", err.Error())
+		return
+	}
+
+	err = removeVaultObject(ctx, r.client, mode, "Accounts/"+url.PathEscape(currState.ID.ValueString()))
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to delete account",
+			fmt.Sprintf("Deleting account [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
+	}
+
+}
+
+// ImportState imports an existing vault account by its account ID.
+func (r *dbAccountResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// setDBAccountProperties copies the database account returned by the vault into the terraform model.
+// The secret is never returned by the vault and is left untouched.
+func setDBAccountProperties(model *dbCredModel, account *vaultAccount) {
+
+	if account.CredID != nil {
+		model.ID = htypes.StringValue(*account.CredID)
+	}
+
+	model.Name = stringValue(account.Name)
+	model.Address = stringValue(account.Address)
+	model.Username = stringValue(account.UserName)
+	model.Platform = stringValue(account.Platform)
+	model.Safe = stringValue(account.SafeName)
+	model.SecretType = stringValue(account.SecretType)
+
+	if account.SecretMgmt != nil {
+		model.Manage = boolValue(account.SecretMgmt.AutomaticManagement)
+		model.ManageReason = stringValue(account.SecretMgmt.ManualManagementReason)
+
+		// Set last updated time to last updated time in the vault
+		if account.SecretMgmt.ModifiedTime != nil {
+			newTime := time.Unix(*account.SecretMgmt.ModifiedTime, 0)
+			model.LastUpdated = htypes.StringValue(newTime.Format(time.RFC850))
+		}
+	}
+
+	props := account.PlatformProps
+
+	// DB Props
+	model.DBPort = stringValue(platformPropertyValue(props, dbPropertyPort))
+
+	// Oracle databases are onboarded from the oracle options, only refresh what was configured there
+	if model.Oracle != nil {
+		dsn := platformPropertyValue(props, dbPropertyDSN)
+
+		if !model.Oracle.ServiceName.IsNull() {
+			model.Oracle.ServiceName = stringValue(platformPropertyValue(props, dbPropertyDatabase))
+			model.DBDSN = stringValue(dsn)
+		}
+
+		// The SID is read back from the onboarded connect descriptor, any other dsn is left in db_dsn so the drift is planned
+		if !model.Oracle.SID.IsNull() {
+			model.Oracle.SID = htypes.StringNull()
+			model.DBDSN = stringValue(dsn)
+
+			if dsn != nil {
+				if sid, ok := oracleDescriptorSID(*dsn); ok {
+					model.Oracle.SID = htypes.StringValue(sid)
+					model.DBDSN = htypes.StringNull()
+				}
+			}
+		}
+	} else {
+		model.DBName = stringValue(platformPropertyValue(props, dbPropertyDatabase))
+		model.DBDSN = stringValue(platformPropertyValue(props, dbPropertyDSN))
+	}
+
+	if model.MSSQL != nil {
+		model.MSSQL.Instance = stringValue(platformPropertyValue(props, dbPropertyInstance))
+	}
+
+	if model.PostgreSQL != nil {
+		model.PostgreSQL.SSLMode = stringValue(platformPropertyValue(props, dbPropertySSLMode))
+	}
+
+	if model.MySQL != nil {
+		model.MySQL.SSLMode = stringValue(platformPropertyValue(props, dbPropertySSLMode))
+	}
+
+	if model.MongoDB != nil {
+		model.MongoDB.AuthDatabase = stringValue(platformPropertyValue(props, dbPropertyAuthDatabase))
+		model.MongoDB.ReplicaSet = stringValue(platformPropertyValue(props, dbPropertyReplicaSet))
+	}
+}
+
+// platformProperties converts the database options into vault platform account properties.
+func (m *dbCredModel) platformProperties(address string) map[string]string {
+
+	props := map[string]string{}
+
+	set := func(key string, value htypes.String) {
+		if !value.IsNull() && !value.IsUnknown() {
+			props[key] = value.ValueString()
+		}
+	}
+
+	set(dbPropertyPort, m.DBPort)
+	set(dbPropertyDatabase, m.DBName)
+	set(dbPropertyDSN, m.DBDSN)
+
+	if m.MSSQL != nil {
+		set(dbPropertyInstance, m.MSSQL.Instance)
+	}
+
+	if m.Oracle != nil {
+		set(dbPropertyDatabase, m.Oracle.ServiceName)
+
+		// Oracle platforms connect by service name, a SID is only reachable through a connect descriptor
+		if !m.Oracle.SID.IsNull() {
+			props[dbPropertyDSN] = fmt.Sprintf("(DESCRIPTION=(ADDRESS=(PROTOCOL=TCP)(HOST=%s)(PORT=%s))(CONNECT_DATA=(SID=%s)))", address, m.DBPort.ValueString(), m.Oracle.SID.ValueString())
+		}
+	}
+
+	if m.PostgreSQL != nil {
+		set(dbPropertySSLMode, m.PostgreSQL.SSLMode)
+	}
+
+	if m.MySQL != nil {
+		set(dbPropertySSLMode, m.MySQL.SSLMode)
+	}
+
+	if m.MongoDB != nil {
+		set(dbPropertyAuthDatabase, m.MongoDB.AuthDatabase)
+		set(dbPropertyReplicaSet, m.MongoDB.ReplicaSet)
+	}
+
+	return props
+}
+
+// oracleSIDPattern matches the SID of an Oracle connect descriptor, keywords are case insensitive and may be padded with spaces.
+var oracleSIDPattern = regexp.MustCompile(`(?i)\(\s*SID\s*=\s*([^()\s]+)\s*\)`)
+
+// oracleDescriptorSID returns the SID of the connect descriptor onboarded in the dsn property for oracle.sid.
+func oracleDescriptorSID(dsn string) (string, bool) {
+
+	match := oracleSIDPattern.FindStringSubmatch(dsn)
+	if match == nil {
+		return "", false
+	}
+
+	return match[1], true
+}
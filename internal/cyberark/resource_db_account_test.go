@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"testing"
+
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestOracleDescriptorSID(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		dsn      string
+		expected string
+		ok       bool
+	}{
+		{"onboarded descriptor", "(DESCRIPTION=(ADDRESS=(PROTOCOL=TCP)(HOST=db.example.com)(PORT=1521))(CONNECT_DATA=(SID=ORCL)))", "ORCL", true},
+		{"lowercase keywords", "(description=(address=(protocol=tcp)(host=db.example.com)(port=1521))(connect_data=(sid=orcl)))", "orcl", true},
+		{"padded keywords", "(DESCRIPTION = (CONNECT_DATA = ( SID = ORCL ) ))", "ORCL", true},
+		{"service name descriptor", "(DESCRIPTION=(ADDRESS=(PROTOCOL=TCP)(HOST=db.example.com)(PORT=1521))(CONNECT_DATA=(SERVICE_NAME=orcl.example.com)))", "", false},
+		{"easy connect", "db.example.com:1521/orcl.example.com", "", false},
+		{"empty sid", "(DESCRIPTION=(CONNECT_DATA=(SID=)))", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			sid, ok := oracleDescriptorSID(tt.dsn)
+
+			if ok != tt.ok {
+				t.Fatalf("expected ok %t, got %t", tt.ok, ok)
+			}
+
+			if sid != tt.expected {
+				t.Errorf("expected SID %q, got %q", tt.expected, sid)
+			}
+		})
+	}
+}
+
+func TestSetDBAccountPropertiesOracle(t *testing.T) {
+
+	descriptor := "(DESCRIPTION=(ADDRESS=(PROTOCOL=TCP)(HOST=db.example.com)(PORT=1521))(CONNECT_DATA=(SID=ORCL2)))"
+
+	tests := []struct {
+		name        string
+		oracle      dbOracleModel
+		props       map[string]interface{}
+		serviceName htypes.String
+		sid         htypes.String
+		dsn         htypes.String
+	}{
+		{
+			name:        "service name and dsn are refreshed",
+			oracle:      dbOracleModel{ServiceName: htypes.StringValue("orcl.example.com"), SID: htypes.StringNull()},
+			props:       map[string]interface{}{"database": "orcl2.example.com", "dsn": "ENABLE=BROKEN"},
+			serviceName: htypes.StringValue("orcl2.example.com"),
+			sid:         htypes.StringNull(),
+			dsn:         htypes.StringValue("ENABLE=BROKEN"),
+		},
+		{
+			name:        "sid is read from the descriptor",
+			oracle:      dbOracleModel{ServiceName: htypes.StringNull(), SID: htypes.StringValue("ORCL")},
+			props:       map[string]interface{}{"dsn": descriptor},
+			serviceName: htypes.StringNull(),
+			sid:         htypes.StringValue("ORCL2"),
+			dsn:         htypes.StringNull(),
+		},
+		{
+			name:        "dsn replaced outside of terraform",
+			oracle:      dbOracleModel{ServiceName: htypes.StringNull(), SID: htypes.StringValue("ORCL")},
+			props:       map[string]interface{}{"dsn": "db.example.com:1521/orcl.example.com"},
+			serviceName: htypes.StringNull(),
+			sid:         htypes.StringNull(),
+			dsn:         htypes.StringValue("db.example.com:1521/orcl.example.com"),
+		},
+		{
+			name:        "dsn removed outside of terraform",
+			oracle:      dbOracleModel{ServiceName: htypes.StringNull(), SID: htypes.StringValue("ORCL")},
+			props:       map[string]interface{}{},
+			serviceName: htypes.StringNull(),
+			sid:         htypes.StringNull(),
+			dsn:         htypes.StringNull(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			oracle := tt.oracle
+			model := dbCredModel{Oracle: &oracle, DBDSN: htypes.StringNull()}
+
+			setDBAccountProperties(&model, &vaultAccount{PlatformProps: tt.props})
+
+			for _, field := range []struct {
+				name     string
+				actual   htypes.String
+				expected htypes.String
+			}{
+				{"oracle.service_name", model.Oracle.ServiceName, tt.serviceName},
+				{"oracle.sid", model.Oracle.SID, tt.sid},
+				{"db_dsn", model.DBDSN, tt.dsn},
+			} {
+				if !field.actual.Equal(field.expected) {
+					t.Errorf("expected %s %s, got %s", field.name, field.expected, field.actual)
+				}
+			}
+		})
+	}
+}
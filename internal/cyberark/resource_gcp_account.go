@@ -0,0 +1,598 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"time"
+
+	cybrtypes "github.com/aharriscybr/cybr-api/pkg/cybr/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &gcpAccountResource{}
+	_ resource.ResourceWithConfigure = &gcpAccountResource{}
+	_ resource.ResourceWithImportState = &gcpAccountResource{}
+	_ resource.ResourceWithConfigValidators = &gcpAccountResource{}
+	_ resource.ResourceWithModifyPlan = &gcpAccountResource{}
+)
+
+// NewGCPAccountResource is a helper function to simplify the provider implementation.
+func NewGCPAccountResource() resource.Resource {
+	return &gcpAccountResource{}
+}
+
+// gcpAccountResource is the resource implementation.
+type gcpAccountResource struct {
+	client vaultClient
+	deletionMode string
+}
+
+// Metadata returns the resource type name.
+func (r *gcpAccountResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_gcpaccount"
+}
+
+type gcpCredModel struct {
+
+	Name 		htypes.String `tfsdk:"name"`
+	Address 	htypes.String `tfsdk:"address"`
+	Username 	htypes.String `tfsdk:"username"`
+	Platform 	htypes.String `tfsdk:"platform"`
+	Safe 		htypes.String `tfsdk:"safe"`
+	SecretType 	htypes.String `tfsdk:"secrettype"`
+	Key			htypes.String `tfsdk:"service_account_key"`
+	KeyFile		htypes.String `tfsdk:"service_account_key_file"`
+	ClientEmail	htypes.String `tfsdk:"client_email"`
+	ProjectID	htypes.String `tfsdk:"project_id"`
+	PrivateKeyID	htypes.String `tfsdk:"private_key_id"`
+	ID 			htypes.String `tfsdk:"id"`
+	LastUpdated htypes.String `tfsdk:"last_updated"`
+	Manage 		htypes.Bool `tfsdk:"sm_manage"`
+	ManageReason	htypes.String `tfsdk:"sm_manage_reason"`
+	DeletionMode htypes.String `tfsdk:"deletion_mode"`
+}
+
+// Platform account properties derived from the service account key.
+const (
+	gcpClientEmail  = "ClientEmail"
+	gcpProjectID    = "ProjectID"
+	gcpPrivateKeyID = "PrivateKeyID"
+)
+
+func (r *gcpAccountResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+
+	resp.Schema = schema.Schema{
+		Description: "GCP Service Account Key Resource",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "CyberArk Privilege Cloud Credential ID- Generated from CyberArk after onboarding account into a safe.",
+				Computed: true,
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"deletion_mode": deletionModeAttribute(),
+			"name": schema.StringAttribute{
+				Description: "Custom Account Name for customizing the object name in a safe.",
+				Required: true,
+			},
+			"address": schema.StringAttribute{
+				Description: "Address of the credential object. Defaults to iam.googleapis.com.",
+				Optional: true,
+				Computed: true,
+				Default: stringdefault.StaticString("iam.googleapis.com"),
+			},
+			"username": schema.StringAttribute{
+				Description: "Username of the Credential object. Defaults to the client_email of the service account key.",
+				Optional: true,
+				Computed: true,
+			},
+			"platform": schema.StringAttribute{
+				Description: "Management Platform associated with the GCP Credential. Defaults to GCPServiceAccount. Changing the platform forces a new account.",
+				Optional: true,
+				Computed: true,
+				Default: stringdefault.StaticString("GCPServiceAccount"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"safe": schema.StringAttribute{
+				Description: "Target Safe where the credential object will be onboarded. Changing the safe forces a new account.",
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"secrettype": schema.StringAttribute{
+				Description: "Secret type of credential. Defaults to key. Changing the secret type forces a new account.",
+				Optional: true,
+				Computed: true,
+				Default: stringdefault.StaticString("key"),
+				Validators: secretTypeValidators(),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"service_account_key": schema.StringAttribute{
+				Description: "Service account JSON key, either as downloaded from GCP or base64 encoded like the private_key of google_service_account_key. Conflicts with service_account_key_file.",
+				Optional: true,
+				Sensitive: true,
+				Validators: []validator.String{
+					gcpServiceAccountKeyValidator{},
+				},
+			},
+			"service_account_key_file": schema.StringAttribute{
+				Description: "Path to a file containing a service account JSON key. The key is read during plan and apply and is not stored in state. Conflicts with service_account_key.",
+				Optional: true,
+			},
+			"client_email": schema.StringAttribute{
+				Description: "Email of the service account, read from the key. Changing the service account replaces the account.",
+				Computed: true,
+			},
+			"project_id": schema.StringAttribute{
+				Description: "Project of the service account, read from the key.",
+				Computed: true,
+			},
+			"private_key_id": schema.StringAttribute{
+				Description: "ID of the service account key, read from the key. A new key ID is uploaded to the vault in place.",
+				Computed: true,
+			},
+			"sm_manage": schema.BoolAttribute{
+				Description: "Automatic Management of a credential. Optional Value.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sm_manage_reason": schema.StringAttribute{
+				Description: "If sm_manage is false, provide reason why credential is not managed.",
+				Optional: true,
+			},
+		},
+	}
+}
+
+// ConfigValidators validate attribute combinations before plan.
+func (r *gcpAccountResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		manualManagementReasonValidator{},
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("service_account_key"),
+			path.MatchRoot("service_account_key_file"),
+		),
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *gcpAccountResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = data.Client
+	r.deletionMode = data.DeletionMode
+}
+
+// ModifyPlan parses the service account key so the derived properties are known at plan time.
+func (r *gcpAccountResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+
+	// Nothing to plan when the resource is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var key, keyFile, username htypes.String
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("service_account_key"), &key)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("service_account_key_file"), &keyFile)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("username"), &username)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Keys only known at apply time are parsed then, missing keys are reported by the config validators
+	if key.IsUnknown() || keyFile.IsUnknown() || (key.IsNull() && keyFile.IsNull()) {
+		return
+	}
+
+	parsed, keyPath, err := loadGCPServiceAccountKey(key, keyFile)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(keyPath, "Invalid service account key", err.Error())
+		return
+	}
+
+	if username.IsNull() {
+		username = htypes.StringValue(parsed.ClientEmail)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("client_email"), parsed.ClientEmail)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("project_id"), parsed.ProjectID)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("private_key_id"), parsed.PrivateKeyID)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("username"), username)...)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	// A key of another service account is a different account, only keys of the same service account are rotated in place
+	var clientEmail htypes.String
+
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("client_email"), &clientEmail)...)
+
+	if !clientEmail.IsNull() && clientEmail.ValueString() != parsed.ClientEmail {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("client_email"))
+	}
+}
+
+// Create a new resource.
+func (r *gcpAccountResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var plan gcpCredModel
+	var sm_props cybrtypes.SecretManagement
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var name, address, username, platform, safe, secrettype, sm_manage_reason string
+
+	var sm_manage bool
+
+	name = os.Getenv("CYBERARK_ACCOUNT_CUSTOM_NAME")
+	safe = os.Getenv("CYBERARK_ACCOUNT_SAFE")
+
+	// Not processing env variable overrides for optional fields, this must be defined in the terraform plan.
+	// This is a design decision.
+	if !plan.Name.IsNull() {
+		name = plan.Name.ValueString()
+	}
+
+	if !plan.Safe.IsNull() {
+		safe = plan.Safe.ValueString()
+	}
+
+	address = plan.Address.ValueString()
+	platform = plan.Platform.ValueString()
+	secrettype = plan.SecretType.ValueString()
+
+	// Key files are read again at apply time, the planned key ID guards against the file changing in between
+	parsed, keyPath, err := loadGCPServiceAccountKey(plan.Key, plan.KeyFile)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(keyPath, "Invalid service account key", err.Error())
+		return
+	}
+
+	if !plan.PrivateKeyID.IsUnknown() && plan.PrivateKeyID.ValueString() != parsed.PrivateKeyID {
+		resp.Diagnostics.AddAttributeError(
+			keyPath,
+			"Service account key changed after plan",
+			fmt.Sprintf("The planned private key ID was %s but the key read during apply has ID %s. Run terraform plan again.", plan.PrivateKeyID.ValueString(), parsed.PrivateKeyID),
+		)
+		return
+	}
+
+	username = parsed.ClientEmail
+	if !plan.Username.IsNull() && !plan.Username.IsUnknown() {
+		username = plan.Username.ValueString()
+	}
+
+	if !plan.Manage.IsNull() && !plan.Manage.IsUnknown() {
+		sm_manage = plan.Manage.ValueBool()
+		sm_props.AutomaticManagement = &sm_manage
+	}
+
+	if !plan.ManageReason.IsNull() {
+		sm_manage_reason = plan.ManageReason.ValueString()
+		sm_props.ManualManagementReason = &sm_manage_reason
+	}
+
+	newAccount := vaultAccountRequest {
+		Name: &name,
+		Address: &address,
+		UserName: &username,
+		Platform: &platform,
+		SafeName: &safe,
+		SecretType: &secrettype,
+		Secret: &parsed.JSON,
+		SecretMgmt: &sm_props,
+		PlatformProps: map[string]string{
+			gcpClientEmail: parsed.ClientEmail,
+			gcpProjectID: parsed.ProjectID,
+			gcpPrivateKeyID: parsed.PrivateKeyID,
+		},
+	}
+
+	create, err := createVaultAccount(ctx, r.client, &newAccount)
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to onboard account",
+			fmt.Sprintf("Onboarding account [%s] into safe [%s] failed.", name, safe),
+			err,
+		))
+		return
+	}
+
+	plan.ID = htypes.StringValue(create)
+	plan.Username = htypes.StringValue(username)
+	plan.ClientEmail = htypes.StringValue(parsed.ClientEmail)
+	plan.ProjectID = htypes.StringValue(parsed.ProjectID)
+	plan.PrivateKeyID = htypes.StringValue(parsed.PrivateKeyID)
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
+
+	if plan.Manage.IsUnknown() {
+		plan.Manage = htypes.BoolNull()
+	}
+
+	// Set state to fully populated data
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Refresh Existing State
+func (r *gcpAccountResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var currState gcpCredModel
+	diags := req.State.Get(ctx, &currState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var newState vaultAccount
+
+	err := vaultRequest(ctx, r.client, http.MethodGet, "Accounts/"+url.PathEscape(currState.ID.ValueString()), nil, &newState)
+	if err != nil {
+		// Objects removed outside of terraform are dropped from state so they are planned for creation
+		if isNotFound(err) {
+			tflog.Warn(ctx, "Account no longer exists in the vault, removing from state.", map[string]interface{}{"id": currState.ID.ValueString()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to read account",
+			fmt.Sprintf("Retrieving account [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
+	}
+
+	tflog.Info(ctx, "Refreshing state")
+
+	setGCPAccountProperties(&currState, &newState)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, currState)...)
+}
+
+
+// Update patches the changed account properties and uploads a rotated service account key to the vault.
+func (r *gcpAccountResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan, currState gcpCredModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &currState)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Key files are read again at apply time, the planned key ID guards against the file changing in between
+	parsed, keyPath, err := loadGCPServiceAccountKey(plan.Key, plan.KeyFile)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(keyPath, "Invalid service account key", err.Error())
+		return
+	}
+
+	if !plan.PrivateKeyID.IsUnknown() && plan.PrivateKeyID.ValueString() != parsed.PrivateKeyID {
+		resp.Diagnostics.AddAttributeError(
+			keyPath,
+			"Service account key changed after plan",
+			fmt.Sprintf("The planned private key ID was %s but the key read during apply has ID %s. Run terraform plan again.", plan.PrivateKeyID.ValueString(), parsed.PrivateKeyID),
+		)
+		return
+	}
+
+	// The key was unknown during plan when it is computed by another resource, so the replace check runs again here
+	if !currState.ClientEmail.IsNull() && currState.ClientEmail.ValueString() != parsed.ClientEmail {
+		resp.Diagnostics.AddAttributeError(
+			keyPath,
+			"Service account changed",
+			fmt.Sprintf("The key belongs to service account %s but the account holds the key of %s. A key of another service account requires a replacement, run terraform apply with -replace for this resource.", parsed.ClientEmail, currState.ClientEmail.ValueString()),
+		)
+		return
+	}
+
+	if plan.Username.IsUnknown() {
+		plan.Username = htypes.StringValue(parsed.ClientEmail)
+	}
+
+	plan.ClientEmail = htypes.StringValue(parsed.ClientEmail)
+	plan.ProjectID = htypes.StringValue(parsed.ProjectID)
+	plan.PrivateKeyID = htypes.StringValue(parsed.PrivateKeyID)
+
+	id := currState.ID.ValueString()
+
+	var patch vaultAccountPatch
+
+	patch.setString("/name", plan.Name, currState.Name)
+	patch.setString("/address", plan.Address, currState.Address)
+	patch.setString("/userName", plan.Username, currState.Username)
+	patch.setBool("/secretManagement/automaticManagementEnabled", plan.Manage, currState.Manage)
+	patch.setString("/secretManagement/manualManagementReason", plan.ManageReason, currState.ManageReason)
+	patch.setString("/platformAccountProperties/"+gcpClientEmail, plan.ClientEmail, currState.ClientEmail)
+	patch.setString("/platformAccountProperties/"+gcpProjectID, plan.ProjectID, currState.ProjectID)
+
+	if len(patch) > 0 {
+		err = patchVaultAccount(ctx, r.client, id, patch)
+		if err != nil {
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to update account",
+				fmt.Sprintf("Updating the properties of account [%s] failed.", id),
+				err,
+			))
+			return
+		}
+
+		tflog.Info(ctx, "Updated account properties", map[string]interface{}{"id": id})
+	}
+
+	plan.ID = currState.ID
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
+
+	if plan.Manage.IsUnknown() {
+		plan.Manage = currState.Manage
+	}
+
+	// Keys cannot be read back from the vault, record the configured key after an import without uploading it
+	if !currState.PrivateKeyID.IsNull() && currState.PrivateKeyID.ValueString() != parsed.PrivateKeyID {
+
+		err = updateVaultSecret(ctx, r.client, id, parsed.JSON)
+		if err == nil {
+			err = patchVaultAccount(ctx, r.client, id, []vaultPatchOperation{
+				{Op: "replace", Path: "/platformAccountProperties/" + gcpPrivateKeyID, Value: parsed.PrivateKeyID},
+			})
+		}
+
+		if err != nil {
+			// Record the updated properties so only the key is uploaded again
+			plan.Key = currState.Key
+			plan.KeyFile = currState.KeyFile
+			plan.PrivateKeyID = currState.PrivateKeyID
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to update service account key",
+				fmt.Sprintf("Uploading the rotated service account key of account [%s] failed.", id),
+				err,
+			))
+			return
+		}
+
+		tflog.Info(ctx, "Updated account service account key", map[string]interface{}{"id": id, "private_key_id": parsed.PrivateKeyID})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *gcpAccountResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var currState gcpCredModel
+	diags := req.State.Get(ctx, &currState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	mode, err := resolveDeletionMode(currState.DeletionMode, r.deletionMode)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("deletion_mode"), "Invalid deletion mode", err.Error())
+		return
+	}
+
+	err = removeVaultObject(ctx, r.client, mode, "Accounts/"+url.PathEscape(currState.ID.ValueString()))
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to delete account",
+			fmt.Sprintf("Deleting account [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
+	}
+
+}
+
+// ImportState imports an existing vault account by its account ID.
+func (r *gcpAccountResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// loadGCPServiceAccountKey reads and parses the configured service account key.
+// The returned path points at the attribute the key was read from for error reporting.
+func loadGCPServiceAccountKey(value htypes.String, file htypes.String) (*gcpServiceAccountKey, path.Path, error) {
+
+	keyPath := path.Root("service_account_key")
+	if value.IsNull() {
+		keyPath = path.Root("service_account_key_file")
+	}
+
+	key, err := readPrivateKey(value, file)
+	if err != nil {
+		return nil, keyPath, err
+	}
+
+	parsed, err := parseGCPServiceAccountKey(key)
+	if err != nil {
+		return nil, keyPath, err
+	}
+
+	return parsed, keyPath, nil
+}
+
+// setGCPAccountProperties copies the GCP account returned by the vault into the terraform model.
+// The key is never returned by the vault and is left untouched, derived properties are only refreshed when the vault reports them.
+func setGCPAccountProperties(model *gcpCredModel, account *vaultAccount) {
+
+	if account.CredID != nil {
+		model.ID = htypes.StringValue(*account.CredID)
+	}
+
+	model.Name = stringValue(account.Name)
+	model.Address = stringValue(account.Address)
+	model.Username = stringValue(account.UserName)
+	model.Platform = stringValue(account.Platform)
+	model.Safe = stringValue(account.SafeName)
+	model.SecretType = stringValue(account.SecretType)
+
+	if account.SecretMgmt != nil {
+		model.Manage = boolValue(account.SecretMgmt.AutomaticManagement)
+		model.ManageReason = stringValue(account.SecretMgmt.ManualManagementReason)
+
+		// Set last updated time to last updated time in the vault
+		if account.SecretMgmt.ModifiedTime != nil {
+			newTime := time.Unix(*account.SecretMgmt.ModifiedTime, 0)
+			model.LastUpdated = htypes.StringValue(newTime.Format(time.RFC850))
+		}
+	}
+
+	for key, value := range map[string]*htypes.String{
+		gcpClientEmail: &model.ClientEmail,
+		gcpProjectID: &model.ProjectID,
+		gcpPrivateKeyID: &model.PrivateKeyID,
+	} {
+		if v, ok := account.PlatformProps[key]; ok && v != nil {
+			*value = htypes.StringValue(fmt.Sprint(v))
+		}
+	}
+}
@@ -3,14 +3,23 @@ package provider
 import (
 	"context"
 	"fmt"
-	"log"
+	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
-	cybrapi "github.com/aharriscybr/cybr-api"
 	cybrtypes "github.com/aharriscybr/cybr-api/pkg/cybr/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	htypes "github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -19,6 +28,10 @@ import (
 var (
 	_ resource.Resource              = &msAccountResource{}
 	_ resource.ResourceWithConfigure = &msAccountResource{}
+	_ resource.ResourceWithImportState = &msAccountResource{}
+	_ resource.ResourceWithConfigValidators = &msAccountResource{}
+	_ resource.ResourceWithUpgradeState = &msAccountResource{}
+	_ resource.ResourceWithModifyPlan = &msAccountResource{}
 )
 
 // NewMSAccountResource is a helper function to simplify the provider implementation.
@@ -28,7 +41,8 @@ func NewMSAccountResource() resource.Resource {
 
 // msAccountResource is the resource implementation.
 type msAccountResource struct {
-	client *cybrapi.Client
+	client vaultClient
+	deletionMode string
 }
 
 // Metadata returns the resource type name.
@@ -52,15 +66,20 @@ type msCredModel struct {
 	MAppObjectID 		htypes.String `tfsdk:"ms_appobjid"`
 	MKID 		htypes.String `tfsdk:"ms_keyid"`
 	MADID 		htypes.String `tfsdk:"ms_adid"`
-	MDur 		htypes.String `tfsdk:"ms_duration"`
-	MPop 		htypes.String `tfsdk:"ms_pop"`
+	MSubID 		htypes.String `tfsdk:"ms_subscription_id"`
+	MDur 		htypes.Int64 `tfsdk:"ms_duration"`
+	MPop 		htypes.Bool `tfsdk:"ms_pop"`
 	MKeyDesc 		htypes.String `tfsdk:"ms_keydesc"`
+	MKeyCreated 		htypes.String `tfsdk:"ms_key_created"`
+	MKeyExpiration 		htypes.String `tfsdk:"ms_key_expiration"`
 
+	DeletionMode htypes.String `tfsdk:"deletion_mode"`
 }
 
 func (r *msAccountResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Microsoft Azure Key Account Resource",
+		Description: "Microsoft Azure Application Secret Account Resource",
+		Version: 1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "CyberArk Privilege Cloud Credential ID- Generated from CyberArk after onboarding account into a safe.",
@@ -69,6 +88,7 @@ func (r *msAccountResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"last_updated": schema.StringAttribute{
 				Computed: true,
 			},
+			"deletion_mode": deletionModeAttribute(),
 			"name": schema.StringAttribute{
 				Description: "Custom Account Name for customizing the object name in a safe.",
 				Required: true,
@@ -82,16 +102,26 @@ func (r *msAccountResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Required: true,
 			},
 			"platform": schema.StringAttribute{
-				Description: "Management Platform associated with the Database Credential.",
+				Description: "Management Platform associated with the Database Credential. Changing the platform forces a new account.",
 				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"safe": schema.StringAttribute{
-				Description: "Target Safe where the credential object will be onboarded.",
+				Description: "Target Safe where the credential object will be onboarded. Changing the safe forces a new account.",
 				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"secrettype": schema.StringAttribute{
-				Description: "Secret type of credential, should always be password unless working with AWS Keys.",
+				Description: "Secret type of credential, should always be password unless working with AWS or SSH keys. Changing the secret type forces a new account.",
 				Required: true,
+				Validators: secretTypeValidators(),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"secret": schema.StringAttribute{
 				Description: "Password of the credential object.",
@@ -101,78 +131,115 @@ func (r *msAccountResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"sm_manage": schema.BoolAttribute{
 				Description: "Automatic Management of a credential. Optional Value.",
 				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"sm_manage_reason": schema.StringAttribute{
 				Description: "If sm_manage is false, provide reason why credential is not managed.",
 				Optional: true,
 			},
 			"ms_appid": schema.StringAttribute{
-				Description: "Microsoft Azure Application ID.",
+				Description: "Microsoft Azure Application (client) ID of the app registration.",
 				Required: true,
+				Validators: guidValidators(),
 			},
 			"ms_appobjid": schema.StringAttribute{
-				Description: "Microsoft Azure Application Object ID.",
+				Description: "Microsoft Azure Application Object ID of the app registration.",
 				Required: true,
+				Validators: guidValidators(),
 			},
 			"ms_keyid": schema.StringAttribute{
-				Description: "Microsoft Azure Key ID.",
+				Description: "Microsoft Azure Key ID of the client secret managed by this account.",
 				Required: true,
+				Validators: guidValidators(),
 			},
 			"ms_adid": schema.StringAttribute{
-				Description: "Microsoft Azure Active Directory ID.",
+				Description: "Microsoft Entra ID (Azure Active Directory) tenant ID the app registration belongs to.",
 				Optional: true,
+				Validators: guidValidators(),
 			},
-			"ms_duration": schema.StringAttribute{
-				Description: "Duration.",
+			"ms_subscription_id": schema.StringAttribute{
+				Description: "Microsoft Azure subscription ID the service principal is used with.",
 				Optional: true,
+				Validators: guidValidators(),
 			},
-			"ms_pop": schema.StringAttribute{
-				Description: "Populate if not exist.",
+			"ms_duration": schema.Int64Attribute{
+				Description: "Number of days a client secret created by the CPM stays valid.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"ms_pop": schema.BoolAttribute{
+				Description: "Create the client secret in Azure when the key ID does not exist yet.",
 				Optional: true,
 			},
 			"ms_keydesc": schema.StringAttribute{
-				Description: "Key Description.",
+				Description: "Description of the client secret in Azure.",
 				Optional: true,
 			},
+			"ms_key_created": schema.StringAttribute{
+				Description: "Time the client secret was last changed in the vault.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ms_key_expiration": schema.StringAttribute{
+				Description: "Time the client secret expires, based on ms_duration and the last time it was changed in the vault. Recomputed during plan when ms_duration changes.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 	}
 }
 
+// ConfigValidators validate attribute combinations before plan.
+func (r *msAccountResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		manualManagementReasonValidator{},
+	}
+}
+
 // Configure adds the provider configured client to the resource.
 func (r *msAccountResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*cybrapi.Client)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *cybrapi.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = data.Client
+	r.deletionMode = data.DeletionMode
 }
 
 // Create a new resource.
 func (r *msAccountResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 
 	var plan msCredModel
-	var props cybrtypes.AccountProps
 	var sm_props cybrtypes.SecretManagement
 
-	resp.Diagnostics.Append(req.Config.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// Strings
-	var name, address, username, platform, safe, secrettype, secret, ms_adid, ms_duration, ms_pop, ms_keydesc, ms_appobjid, ms_keyid, ms_appid, sm_manage_reason string
+	var name, address, username, platform, safe, secrettype, secret, sm_manage_reason string
 
 	// Boolean
 	var sm_manage bool
@@ -204,10 +271,6 @@ func (r *msAccountResource) Create(ctx context.Context, req resource.CreateReque
 		platform = plan.Platform.ValueString()
 	}
 
-	if !plan.Platform.IsNull() {
-		platform = plan.Platform.ValueString()
-	}
-
 	if !plan.Safe.IsNull() {
 		safe = plan.Safe.ValueString()
 	}
@@ -220,7 +283,7 @@ func (r *msAccountResource) Create(ctx context.Context, req resource.CreateReque
 		secret = plan.Secret.ValueString()
 	}
 
-	if !plan.Manage.IsNull() {
+	if !plan.Manage.IsNull() && !plan.Manage.IsUnknown() {
 		sm_manage = plan.Manage.ValueBool()
 		sm_props.AutomaticManagement = &sm_manage
 	}
@@ -230,77 +293,206 @@ func (r *msAccountResource) Create(ctx context.Context, req resource.CreateReque
 		sm_props.ManualManagementReason = &sm_manage_reason
 	}
 
-	if !plan.MAppID.IsNull() {
-		ms_appid = plan.MAppID.ValueString()
-		props.MAppID = &ms_appid
+	newAccount := vaultAccountRequest {
+		Name: &name,
+		Address: &address,
+		UserName: &username,
+		Platform: &platform,
+		SafeName: &safe,
+		SecretType: &secrettype,
+		Secret: &secret,
+		SecretMgmt: &sm_props,
+		PlatformProps: plan.platformProperties(),
 	}
 
-	if !plan.MAppObjectID.IsNull() {
-		ms_appobjid = plan.MAppObjectID.ValueString()
-		props.MAppObjectID = &ms_appobjid
+	create, err := createVaultAccount(ctx, r.client, &newAccount)
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to onboard account",
+			fmt.Sprintf("Onboarding account [%s] into safe [%s] failed.", name, safe),
+			err,
+		))
+		return
 	}
 
-	if !plan.MKID.IsNull() {
-		ms_keyid = plan.MKID.ValueString()
-		props.MKID = &ms_keyid
+	plan.ID = htypes.StringValue(create)
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
+
+	if plan.Manage.IsUnknown() {
+		plan.Manage = htypes.BoolNull()
 	}
 
-	if !plan.MADID.IsNull() {
-		ms_adid = plan.MADID.ValueString()
-		props.MADID = &ms_adid
+	plan.MKeyCreated = htypes.StringNull()
+	plan.MKeyExpiration = htypes.StringNull()
+
+	// Key timestamps are taken from the vault, the account is already onboarded so a failed lookup only leaves them unset
+	var account vaultAccount
+
+	err = vaultRequest(ctx, r.client, http.MethodGet, "Accounts/"+url.PathEscape(create), nil, &account)
+	if err != nil {
+		tflog.Warn(ctx, "Unable to read key timestamps of the onboarded account.", map[string]interface{}{"id": create, "error": err.Error()})
+	} else {
+		plan.setKeyTimestamps(&account)
 	}
 
-	if !plan.MDur.IsNull() {
-		ms_duration = plan.MDur.ValueString()
-		props.MDur = &ms_duration
+	// Set state to fully populated data
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Refresh Existing State
+func (r *msAccountResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var currState msCredModel
+	diags := req.State.Get(ctx, &currState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if !plan.MPop.IsNull() {
-		ms_pop = plan.MPop.ValueString()
-		props.MPop = &ms_pop
+	var newState vaultAccount
+
+	err := vaultRequest(ctx, r.client, http.MethodGet, "Accounts/"+url.PathEscape(currState.ID.ValueString()), nil, &newState)
+	if err != nil {
+		// Objects removed outside of terraform are dropped from state so they are planned for creation
+		if isNotFound(err) {
+			tflog.Warn(ctx, "Account no longer exists in the vault, removing from state.", map[string]interface{}{"id": currState.ID.ValueString()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to read account",
+			fmt.Sprintf("Retrieving account [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
 	}
 
-	if !plan.MKeyDesc.IsNull() {
-		ms_keydesc = plan.MKeyDesc.ValueString()
-		props.MKeyDesc = &ms_keydesc
+	tflog.Info(ctx, "Refreshing state")
+
+	resp.Diagnostics.Append(setMSAccountProperties(&currState, &newState)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	newAccount := cybrtypes.Credential {
-		Name: &name,
-		Address: &address,
-		UserName: &username,
-		Platform: &platform,
-		SafeName: &safe,
-		SecretType: &secrettype,
-		Secret: &secret,
-		Props: &props,
-		SecretMgmt: &sm_props,
+	resp.Diagnostics.Append(resp.State.Set(ctx, currState)...)
+}
+
+
+// ModifyPlan recomputes the key expiration when ms_duration changes and plans new key timestamps when the secret changes.
+func (r *msAccountResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+
+	// Key timestamps are only known once the account exists, nothing to plan on create or destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
 	}
 
-	create, err := cybrapi.CreateAccount(&newAccount, r.client.AuthToken, r.client.Domain)
-	if err != nil {
-		log.Fatal(err)
+	var plan, currState msCredModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &currState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The vault records a new change time when the secret is updated
+	if !currState.Secret.IsNull() && !plan.Secret.Equal(currState.Secret) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ms_key_created"), htypes.StringUnknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ms_key_expiration"), htypes.StringUnknown())...)
+		return
 	}
 
-	if create == "" || len(create) == 0 {
+	if !plan.MDur.Equal(currState.MDur) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ms_key_expiration"), msKeyExpiration(currState.MKeyCreated, plan.MDur))...)
+	}
+}
 
-		tflog.Error(ctx, "Error catching ID, this means provisioning failed in pipeline. Please check debug logs and try again.")
+// Update patches the changed account and Azure properties and updates the secret in the vault.
+func (r *msAccountResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 
-	} else {
+	var plan, currState msCredModel
 
-		plan.ID = htypes.StringValue(create)
-		plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
-	
-		// Set state to fully populated data
-		resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &currState)...)
 
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	
+	id := currState.ID.ValueString()
+
+	var patch vaultAccountPatch
+
+	patch.setString("/name", plan.Name, currState.Name)
+	patch.setString("/address", plan.Address, currState.Address)
+	patch.setString("/userName", plan.Username, currState.Username)
+	patch.setBool("/secretManagement/automaticManagementEnabled", plan.Manage, currState.Manage)
+	patch.setString("/secretManagement/manualManagementReason", plan.ManageReason, currState.ManageReason)
+	patch.setPlatformProperties(plan.platformProperties(), currState.platformProperties())
+
+	if len(patch) > 0 {
+		err := patchVaultAccount(ctx, r.client, id, patch)
+		if err != nil {
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to update account",
+				fmt.Sprintf("Updating the properties of account [%s] failed.", id),
+				err,
+			))
+			return
+		}
+
+		tflog.Info(ctx, "Updated account properties", map[string]interface{}{"id": id})
+	}
+
+	plan.ID = currState.ID
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
+
+	if plan.Manage.IsUnknown() {
+		plan.Manage = currState.Manage
+	}
+
+	// Secrets cannot be read back from the vault, record the configured secret after an import without updating it
+	if !currState.Secret.IsNull() && !plan.Secret.Equal(currState.Secret) {
+
+		err := updateVaultSecret(ctx, r.client, id, plan.Secret.ValueString())
+		if err != nil {
+			// Record the updated properties so only the secret is updated again
+			plan.Secret = currState.Secret
+			plan.MKeyCreated = currState.MKeyCreated
+			plan.MKeyExpiration = msKeyExpiration(currState.MKeyCreated, plan.MDur)
+			if plan.MKeyExpiration.IsUnknown() {
+				plan.MKeyExpiration = currState.MKeyExpiration
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to update account secret",
+				fmt.Sprintf("Updating the secret of account [%s] failed.", id),
+				err,
+			))
+			return
+		}
+
+		tflog.Info(ctx, "Updated account secret", map[string]interface{}{"id": id})
+	}
+
+	// Key timestamps planned as unknown are taken from the vault, a failed lookup only leaves them unset
+	if plan.MKeyCreated.IsUnknown() || plan.MKeyExpiration.IsUnknown() {
+		var account vaultAccount
+
+		err := vaultRequest(ctx, r.client, http.MethodGet, "Accounts/"+url.PathEscape(id), nil, &account)
+		if err != nil {
+			tflog.Warn(ctx, "Unable to read key timestamps of the updated account.", map[string]interface{}{"id": id, "error": err.Error()})
+		}
+
+		plan.setKeyTimestamps(&account)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
-// Refresh Existing State
-func (r *msAccountResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *msAccountResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 
 	var currState msCredModel
 	diags := req.State.Get(ctx, &currState)
@@ -309,47 +501,314 @@ func (r *msAccountResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	newState, err := cybrapi.GetAccount(currState.ID.ValueStringPointer(), r.client.AuthToken, r.client.Domain)
+	mode, err := resolveDeletionMode(currState.DeletionMode, r.deletionMode)
 	if err != nil {
-		tflog.Error(ctx, "Unable to retrieve state from CyberArk API")
+		resp.Diagnostics.AddAttributeError(path.Root("deletion_mode"), "Invalid deletion mode", err.Error())
+		return
 	}
 
-	tflog.Info(ctx, "Refreshing state")
+	err = removeVaultObject(ctx, r.client, mode, "Accounts/"+url.PathEscape(currState.ID.ValueString()))
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to delete account",
+			fmt.Sprintf("Deleting account [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
+	}
+
+}
+
+// ImportState imports an existing vault account by its account ID.
+func (r *msAccountResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// Platform account properties of the Azure application secret platform.
+const (
+	msApplicationID       = "ApplicationID"
+	msApplicationObjectID = "ApplicationObjectID"
+	msKeyID               = "KeyID"
+	msActiveDirectoryID   = "ActiveDirectoryID"
+	msSubscriptionID      = "SubscriptionID"
+	msDuration            = "Duration"
+	msPopulateIfNotExist  = "PopulateIfNotExist"
+	msKeyDescription      = "KeyDescription"
+)
+
+// platformProperties converts the configured Azure properties into vault platform account properties.
+func (m *msCredModel) platformProperties() map[string]string {
+
+	props := map[string]string{}
+
+	for key, value := range map[string]htypes.String{
+		msApplicationID: m.MAppID,
+		msApplicationObjectID: m.MAppObjectID,
+		msKeyID: m.MKID,
+		msActiveDirectoryID: m.MADID,
+		msSubscriptionID: m.MSubID,
+		msKeyDescription: m.MKeyDesc,
+	} {
+		if !value.IsNull() {
+			props[key] = value.ValueString()
+		}
+	}
+
+	if !m.MDur.IsNull() {
+		props[msDuration] = strconv.FormatInt(m.MDur.ValueInt64(), 10)
+	}
+
+	if !m.MPop.IsNull() {
+		props[msPopulateIfNotExist] = "No"
+
+		if m.MPop.ValueBool() {
+			props[msPopulateIfNotExist] = "Yes"
+		}
+	}
+
+	return props
+}
+
+// setKeyTimestamps sets the time the client secret was last changed and when it expires from the vault.
+func (m *msCredModel) setKeyTimestamps(account *vaultAccount) {
+
+	m.MKeyCreated = htypes.StringNull()
+	m.MKeyExpiration = htypes.StringNull()
+
+	if account.SecretMgmt == nil || account.SecretMgmt.ModifiedTime == nil || *account.SecretMgmt.ModifiedTime == 0 {
+		return
+	}
+
+	changed := time.Unix(*account.SecretMgmt.ModifiedTime, 0)
+	m.MKeyCreated = htypes.StringValue(changed.Format(time.RFC850))
+
+	if m.MDur.IsNull() {
+		return
+	}
+
+	m.MKeyExpiration = htypes.StringValue(changed.AddDate(0, 0, int(m.MDur.ValueInt64())).Format(time.RFC850))
+}
+
+// msKeyExpiration returns when a client secret last changed at created expires after duration days.
+// The expiration is unknown when the change time cannot be parsed, it is then refreshed from the vault.
+func msKeyExpiration(created htypes.String, duration htypes.Int64) htypes.String {
+
+	if created.IsUnknown() || duration.IsUnknown() {
+		return htypes.StringUnknown()
+	}
+
+	if created.IsNull() || duration.IsNull() {
+		return htypes.StringNull()
+	}
+
+	changed, err := time.Parse(time.RFC850, created.ValueString())
+	if err != nil {
+		return htypes.StringUnknown()
+	}
+
+	return htypes.StringValue(changed.AddDate(0, 0, int(duration.ValueInt64())).Format(time.RFC850))
+}
+
+// msDurationValue converts the Duration platform property into a number of days.
+func msDurationValue(v *string) (htypes.Int64, error) {
 
-	// Main Credentials
-	currState.Name = htypes.StringValue(*newState.Name)
-	
-	currState.Address = htypes.StringValue(*newState.Address)
-	currState.Platform = htypes.StringValue(*newState.Platform)
-	currState.Safe = htypes.StringValue(*newState.SafeName)
-	currState.Username = htypes.StringValue(*newState.UserName)
-	currState.SecretType = htypes.StringValue(*newState.SecretType)
+	if v == nil || strings.TrimSpace(*v) == "" {
+		return htypes.Int64Null(), nil
+	}
+
+	days, err := strconv.ParseInt(strings.TrimSpace(*v), 10, 64)
+	if err != nil {
+		return htypes.Int64Null(), fmt.Errorf("duration [%s] is not a number of days", *v)
+	}
+
+	return htypes.Int64Value(days), nil
+}
+
+// msPopulateValue converts the PopulateIfNotExist platform property into a flag.
+func msPopulateValue(v *string) (htypes.Bool, error) {
+
+	if v == nil || strings.TrimSpace(*v) == "" {
+		return htypes.BoolNull(), nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(*v)) {
+	case "yes", "true":
+		return htypes.BoolValue(true), nil
+	case "no", "false":
+		return htypes.BoolValue(false), nil
+	}
+
+	return htypes.BoolNull(), fmt.Errorf("populate if not exist [%s] is not yes or no", *v)
+}
+
+// setMSAccountProperties copies the Azure account returned by the vault into the terraform model.
+// The secret is never returned by the vault and is left untouched.
+func setMSAccountProperties(model *msCredModel, account *vaultAccount) diag.Diagnostics {
+
+	var diags diag.Diagnostics
+
+	if account.CredID != nil {
+		model.ID = htypes.StringValue(*account.CredID)
+	}
+
+	model.Name = stringValue(account.Name)
+	model.Address = stringValue(account.Address)
+	model.Username = stringValue(account.UserName)
+	model.Platform = stringValue(account.Platform)
+	model.Safe = stringValue(account.SafeName)
+	model.SecretType = stringValue(account.SecretType)
+
+	if account.SecretMgmt != nil {
+		model.Manage = boolValue(account.SecretMgmt.AutomaticManagement)
+		model.ManageReason = stringValue(account.SecretMgmt.ManualManagementReason)
+
+		// Set last updated time to last updated time in the vault
+		if account.SecretMgmt.ModifiedTime != nil {
+			newTime := time.Unix(*account.SecretMgmt.ModifiedTime, 0)
+			model.LastUpdated = htypes.StringValue(newTime.Format(time.RFC850))
+		}
+	}
 
 	// MS Props
-	currState.MAppID = htypes.StringValue(*newState.Props.MAppID)
-	currState.MAppObjectID = htypes.StringValue(*newState.Props.MAppObjectID)
-	currState.MKID = htypes.StringValue(*newState.Props.MKID)
-	currState.MADID = htypes.StringValue(*newState.Props.MADID)
-	currState.MDur = htypes.StringValue(*newState.Props.MDur)
-	currState.MPop = htypes.StringValue(*newState.Props.MPop)
-	currState.MKeyDesc = htypes.StringValue(*newState.Props.MKeyDesc)
-	
-	// Set last updated time to last updated time in the vault
-	newTime := time.Unix(*newState.SecretMgmt.ModifiedTime, 0)
-	currState.LastUpdated = htypes.StringValue(newTime.Format(time.RFC850))
-
-	// Ensure ID is consistent
-	currState.ID = htypes.StringValue(*newState.CredID)
+	props := account.PlatformProps
+
+	model.MAppID = stringValue(platformPropertyValue(props, msApplicationID))
+	model.MAppObjectID = stringValue(platformPropertyValue(props, msApplicationObjectID))
+	model.MKID = stringValue(platformPropertyValue(props, msKeyID))
+	model.MADID = stringValue(platformPropertyValue(props, msActiveDirectoryID))
+	model.MSubID = stringValue(platformPropertyValue(props, msSubscriptionID))
+	model.MKeyDesc = stringValue(platformPropertyValue(props, msKeyDescription))
 
+	duration, err := msDurationValue(platformPropertyValue(props, msDuration))
+	if err != nil {
+		diags.AddAttributeError(path.Root("ms_duration"), "Invalid account property", fmt.Sprintf("The vault reported an invalid %s property: %s.", msDuration, err))
+	}
+	model.MDur = duration
+
+	pop, err := msPopulateValue(platformPropertyValue(props, msPopulateIfNotExist))
+	if err != nil {
+		diags.AddAttributeError(path.Root("ms_pop"), "Invalid account property", fmt.Sprintf("The vault reported an invalid %s property: %s.", msPopulateIfNotExist, err))
+	}
+	model.MPop = pop
+
+	model.setKeyTimestamps(account)
+
+	return diags
 }
 
+// UpgradeState converts state written before the Azure properties were typed.
+func (r *msAccountResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: msAccountSchemaV0(),
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+
+				var prior msCredModelV0
+
+				resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				duration, err := msDurationValue(prior.MDur.ValueStringPointer())
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(path.Root("ms_duration"), "Unable to upgrade state", err.Error())
+				}
+
+				pop, err := msPopulateValue(prior.MPop.ValueStringPointer())
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(path.Root("ms_pop"), "Unable to upgrade state", err.Error())
+				}
+
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				// Key timestamps are filled in by the next refresh
+				upgraded := msCredModel{
+					Name: prior.Name,
+					Address: prior.Address,
+					Username: prior.Username,
+					Platform: prior.Platform,
+					Safe: prior.Safe,
+					SecretType: prior.SecretType,
+					Secret: prior.Secret,
+					ID: prior.ID,
+					LastUpdated: prior.LastUpdated,
+					Manage: prior.Manage,
+					ManageReason: prior.ManageReason,
+					MAppID: prior.MAppID,
+					MAppObjectID: prior.MAppObjectID,
+					MKID: prior.MKID,
+					MADID: prior.MADID,
+					MSubID: htypes.StringNull(),
+					MDur: duration,
+					MPop: pop,
+					MKeyDesc: prior.MKeyDesc,
+					MKeyCreated: htypes.StringNull(),
+					MKeyExpiration: htypes.StringNull(),
+					DeletionMode: prior.DeletionMode,
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, upgraded)...)
+			},
+		},
+	}
+}
 
-// Update updates the resource and sets the updated Terraform state on success.
-func (r *msAccountResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	tflog.Error(ctx, "Update is not supported through terraform. Please consult with your CyberArk Administrator to process account property updates.")
+// msCredModelV0 is the state of version 0, where every Azure property was a string.
+type msCredModelV0 struct {
+	Name 		htypes.String `tfsdk:"name"`
+	Address 	htypes.String `tfsdk:"address"`
+	Username 	htypes.String `tfsdk:"username"`
+	Platform 	htypes.String `tfsdk:"platform"`
+	Safe 		htypes.String `tfsdk:"safe"`
+	SecretType 	htypes.String `tfsdk:"secrettype"`
+	Secret 		htypes.String `tfsdk:"secret"`
+	ID 			htypes.String `tfsdk:"id"`
+	LastUpdated htypes.String `tfsdk:"last_updated"`
+	Manage 		htypes.Bool `tfsdk:"sm_manage"`
+	ManageReason	htypes.String `tfsdk:"sm_manage_reason"`
+	MAppID 		htypes.String `tfsdk:"ms_appid"`
+	MAppObjectID 		htypes.String `tfsdk:"ms_appobjid"`
+	MKID 		htypes.String `tfsdk:"ms_keyid"`
+	MADID 		htypes.String `tfsdk:"ms_adid"`
+	MDur 		htypes.String `tfsdk:"ms_duration"`
+	MPop 		htypes.String `tfsdk:"ms_pop"`
+	MKeyDesc 		htypes.String `tfsdk:"ms_keydesc"`
+
+	DeletionMode htypes.String `tfsdk:"deletion_mode"`
 }
 
-// Delete deletes the resource and removes the Terraform state on success.
-func (r *msAccountResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	tflog.Info(ctx, "Delete is not supported through terraform. Please consult with your CyberArk Administrator to process deleting this resource.")
-}
\ No newline at end of file
+// msAccountSchemaV0 is the schema of version 0, only the attribute types are needed to decode prior state.
+func msAccountSchemaV0() *schema.Schema {
+
+	attributes := map[string]schema.Attribute{
+		"sm_manage": schema.BoolAttribute{
+			Optional: true,
+			Computed: true,
+		},
+	}
+
+	for _, name := range []string{"id", "last_updated"} {
+		attributes[name] = schema.StringAttribute{Computed: true}
+	}
+
+	for _, name := range []string{"name", "address", "username", "platform", "safe", "secrettype", "ms_appid", "ms_appobjid", "ms_keyid"} {
+		attributes[name] = schema.StringAttribute{Required: true}
+	}
+
+	for _, name := range []string{"sm_manage_reason", "ms_adid", "ms_duration", "ms_pop", "ms_keydesc", "deletion_mode"} {
+		attributes[name] = schema.StringAttribute{Optional: true}
+	}
+
+	attributes["secret"] = schema.StringAttribute{
+		Required: true,
+		Sensitive: true,
+	}
+
+	return &schema.Schema{
+		Attributes: attributes,
+	}
+}
@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// msPriorStateV0 returns version 0 state with the given Azure duration and populate flag.
+func msPriorStateV0(duration htypes.String, pop htypes.String) msCredModelV0 {
+	return msCredModelV0{
+		Name:         htypes.StringValue("azure-app"),
+		Address:      htypes.StringValue("portal.azure.com"),
+		Username:     htypes.StringValue("app"),
+		Platform:     htypes.StringValue("AzureApplicationSecret"),
+		Safe:         htypes.StringValue("azure"),
+		SecretType:   htypes.StringValue("password"),
+		Secret:       htypes.StringValue("secret"),
+		ID:           htypes.StringValue("12_3"),
+		LastUpdated:  htypes.StringValue("Monday, 01-Jan-24 00:00:00 UTC"),
+		Manage:       htypes.BoolValue(true),
+		ManageReason: htypes.StringNull(),
+		MAppID:       htypes.StringValue("00000000-0000-0000-0000-000000000001"),
+		MAppObjectID: htypes.StringValue("00000000-0000-0000-0000-000000000002"),
+		MKID:         htypes.StringValue("00000000-0000-0000-0000-000000000003"),
+		MADID:        htypes.StringNull(),
+		MDur:         duration,
+		MPop:         pop,
+		MKeyDesc:     htypes.StringNull(),
+		DeletionMode: htypes.StringNull(),
+	}
+}
+
+func TestMSAccountUpgradeStateV0(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		duration htypes.String
+		pop      htypes.String
+		expDur   htypes.Int64
+		expPop   htypes.Bool
+		err      bool
+	}{
+		{"typed properties", htypes.StringValue("30"), htypes.StringValue("Yes"), htypes.Int64Value(30), htypes.BoolValue(true), false},
+		{"populate no", htypes.StringValue(" 90 "), htypes.StringValue("no"), htypes.Int64Value(90), htypes.BoolValue(false), false},
+		{"populate true", htypes.StringValue("1"), htypes.StringValue("true"), htypes.Int64Value(1), htypes.BoolValue(true), false},
+		{"unset properties", htypes.StringNull(), htypes.StringNull(), htypes.Int64Null(), htypes.BoolNull(), false},
+		{"empty properties", htypes.StringValue(""), htypes.StringValue(""), htypes.Int64Null(), htypes.BoolNull(), false},
+		{"invalid duration", htypes.StringValue("thirty"), htypes.StringNull(), htypes.Int64Null(), htypes.BoolNull(), true},
+		{"invalid populate", htypes.StringNull(), htypes.StringValue("maybe"), htypes.Int64Null(), htypes.BoolNull(), true},
+	}
+
+	ctx := context.Background()
+	r := &msAccountResource{}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	upgrader, ok := r.UpgradeState(ctx)[0]
+	if !ok {
+		t.Fatal("expected a state upgrader from version 0")
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			prior := tfsdk.State{Schema: *upgrader.PriorSchema}
+			if diags := prior.Set(ctx, msPriorStateV0(tt.duration, tt.pop)); diags.HasError() {
+				t.Fatalf("unable to set prior state: %v", diags)
+			}
+
+			resp := resource.UpgradeStateResponse{
+				State: tfsdk.State{Schema: schemaResp.Schema},
+			}
+
+			upgrader.StateUpgrader(ctx, resource.UpgradeStateRequest{State: &prior}, &resp)
+
+			if tt.err {
+				if !resp.Diagnostics.HasError() {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+
+			var upgraded msCredModel
+			if diags := resp.State.Get(ctx, &upgraded); diags.HasError() {
+				t.Fatalf("unable to read upgraded state: %v", diags)
+			}
+
+			if !upgraded.MDur.Equal(tt.expDur) {
+				t.Errorf("expected ms_duration %s, got %s", tt.expDur, upgraded.MDur)
+			}
+
+			if !upgraded.MPop.Equal(tt.expPop) {
+				t.Errorf("expected ms_pop %s, got %s", tt.expPop, upgraded.MPop)
+			}
+
+			if upgraded.ID.ValueString() != "12_3" || upgraded.MAppID.ValueString() != "00000000-0000-0000-0000-000000000001" {
+				t.Errorf("expected the remaining attributes to be kept, got id %s and ms_appid %s", upgraded.ID, upgraded.MAppID)
+			}
+
+			if !upgraded.MSubID.IsNull() || !upgraded.MKeyCreated.IsNull() || !upgraded.MKeyExpiration.IsNull() {
+				t.Error("expected attributes added in version 1 to be null")
+			}
+		})
+	}
+}
+
+func TestMSKeyExpiration(t *testing.T) {
+
+	created := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC).Format(time.RFC850)
+
+	tests := []struct {
+		name     string
+		created  htypes.String
+		duration htypes.Int64
+		expected htypes.String
+	}{
+		{"duration in days", htypes.StringValue(created), htypes.Int64Value(30), htypes.StringValue(time.Date(2024, time.February, 14, 10, 30, 0, 0, time.UTC).Format(time.RFC850))},
+		{"no duration", htypes.StringValue(created), htypes.Int64Null(), htypes.StringNull()},
+		{"never changed", htypes.StringNull(), htypes.Int64Value(30), htypes.StringNull()},
+		{"change time unknown", htypes.StringUnknown(), htypes.Int64Value(30), htypes.StringUnknown()},
+		{"duration unknown", htypes.StringValue(created), htypes.Int64Unknown(), htypes.StringUnknown()},
+		{"invalid change time", htypes.StringValue("yesterday"), htypes.Int64Value(30), htypes.StringUnknown()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := msKeyExpiration(tt.created, tt.duration); !actual.Equal(tt.expected) {
+				t.Errorf("expected %s, got %s", tt.expected, actual)
+			}
+		})
+	}
+}
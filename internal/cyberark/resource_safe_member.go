@@ -0,0 +1,408 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &safeMemberResource{}
+	_ resource.ResourceWithConfigure = &safeMemberResource{}
+	_ resource.ResourceWithImportState = &safeMemberResource{}
+	_ resource.ResourceWithConfigValidators = &safeMemberResource{}
+	_ resource.ResourceWithModifyPlan = &safeMemberResource{}
+)
+
+// NewSafeMemberResource is a helper function to simplify the provider implementation.
+func NewSafeMemberResource() resource.Resource {
+	return &safeMemberResource{}
+}
+
+// safeMemberResource is the resource implementation.
+type safeMemberResource struct {
+	client vaultClient
+	deletionMode string
+}
+
+// Metadata returns the resource type name.
+func (r *safeMemberResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_safe_member"
+}
+
+type safeMemberModel struct {
+	ID htypes.String `tfsdk:"id"`
+	SafeName htypes.String `tfsdk:"safe_name"`
+	Member htypes.String `tfsdk:"member"`
+	MemberType htypes.String `tfsdk:"member_type"`
+	SearchIn htypes.String `tfsdk:"search_in"`
+	ExpirationDate htypes.String `tfsdk:"membership_expiration_date"`
+	PermType htypes.String `tfsdk:"permission_level"`
+	Permissions *memberPermissionsModel `tfsdk:"permissions"`
+	LastUpdated htypes.String `tfsdk:"last_updated"`
+	DeletionMode htypes.String `tfsdk:"deletion_mode"`
+}
+
+func (r *safeMemberResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "CyberArk Privilege Cloud Safe Member Resource",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Safe member identifier in the form safe_name/member.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"deletion_mode": deletionModeAttribute(),
+			"safe_name": schema.StringAttribute{
+				Description: "Name of the Safe to add the member to.",
+				Required: true,
+				Validators: safeNameValidators(),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"member": schema.StringAttribute{
+				Description: "Name of the user, group or role to add as a Safe member.",
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"member_type": schema.StringAttribute{
+				Description: "Member type: user, group or role. Defaults to the type of the member found in search_in.",
+				Optional: true,
+				Validators: memberTypeValidators("user", "group", "role"),
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"search_in": schema.StringAttribute{
+				Description: "Where to search for the member: Vault, or the name of the domain or directory the member belongs to. Defaults to Vault.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"membership_expiration_date": schema.StringAttribute{
+				Description: "RFC3339 timestamp when the membership expires, for example 2025-12-31T00:00:00Z. Membership does not expire when unset.",
+				Optional: true,
+			},
+			"permission_level": schema.StringAttribute{
+				Description: permissionLevelDescription(),
+				Optional: true,
+				Validators: permissionLevelValidators(),
+			},
+			"permissions": schema.SingleNestedAttribute{
+				Description: "Effective permissions granted to the member. Permissions set here override permission_level, permissions that are neither set nor granted by permission_level are denied.",
+				Optional: true,
+				Computed: true,
+				Attributes: memberPermissionsAttributes(),
+			},
+		},
+	}
+}
+
+// ConfigValidators require either a permission preset or explicit permissions.
+func (r *safeMemberResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.AtLeastOneOf(
+			path.MatchRoot("permission_level"),
+			path.MatchRoot("permissions"),
+		),
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *safeMemberResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = data.Client
+	r.deletionMode = data.DeletionMode
+}
+
+// ModifyPlan expands permission_level and the permission overrides into the effective permissions.
+func (r *safeMemberResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	planMemberPermissions(ctx, req, resp)
+}
+
+// Create adds the member to the safe.
+func (r *safeMemberResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var plan safeMemberModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	safe_name := plan.SafeName.ValueString()
+	member := plan.Member.ValueString()
+
+	expiration, diags := membershipExpiration(plan.ExpirationDate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	newMember := vaultSafeMember {
+		Name: &member,
+		ExpirationDate: expiration,
+		Permissions: plan.Permissions.vaultPermission(),
+	}
+
+	if !plan.MemberType.IsNull() && !plan.MemberType.IsUnknown() {
+		newMember.Type = plan.MemberType.ValueStringPointer()
+	}
+
+	if !plan.SearchIn.IsNull() {
+		newMember.SearchIn = plan.SearchIn.ValueStringPointer()
+	}
+
+	var created vaultSafeMember
+
+	err := vaultRequest(ctx, r.client, http.MethodPost, safeMembersPath(safe_name), &newMember, &created)
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to add safe member",
+			fmt.Sprintf("Adding member [%s] to safe [%s] failed.", member, safe_name),
+			err,
+		))
+		return
+	}
+
+	tflog.Info(ctx, "Added safe member", map[string]interface{}{"safe": safe_name, "member": member})
+
+	plan.ID = htypes.StringValue(safe_name + "/" + member)
+	setSafeMemberProperties(&plan, &created)
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
+
+	// Set state to fully populated data
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Refresh Existing State
+func (r *safeMemberResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var currState safeMemberModel
+	diags := req.State.Get(ctx, &currState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var member vaultSafeMember
+
+	err := vaultRequest(ctx, r.client, http.MethodGet, safeMemberPath(currState.SafeName.ValueString(), currState.Member.ValueString()), nil, &member)
+	if err != nil {
+		// Members removed outside of terraform are dropped from state so they are planned for creation
+		if isNotFound(err) {
+			tflog.Warn(ctx, "Safe member no longer exists in the vault, removing from state.", map[string]interface{}{"id": currState.ID.ValueString()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to read safe member",
+			fmt.Sprintf("Retrieving member [%s] of safe [%s] from the vault failed.", currState.Member.ValueString(), currState.SafeName.ValueString()),
+			err,
+		))
+		return
+	}
+
+	tflog.Info(ctx, "Refreshing state")
+
+	setSafeMemberProperties(&currState, &member)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, currState)...)
+}
+
+// Update updates the member permissions and expiration date.
+func (r *safeMemberResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan, currState safeMemberModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &currState)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	safe_name := plan.SafeName.ValueString()
+	member := plan.Member.ValueString()
+
+	expiration, diags := membershipExpiration(plan.ExpirationDate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updMember := vaultSafeMember {
+		ExpirationDate: expiration,
+		Permissions: plan.Permissions.vaultPermission(),
+	}
+
+	var updated vaultSafeMember
+
+	err := vaultRequest(ctx, r.client, http.MethodPut, safeMemberPath(safe_name, member), &updMember, &updated)
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to update safe member",
+			fmt.Sprintf("Updating member [%s] of safe [%s] failed.", member, safe_name),
+			err,
+		))
+		return
+	}
+
+	tflog.Info(ctx, "Updated safe member", map[string]interface{}{"safe": safe_name, "member": member})
+
+	plan.ID = currState.ID
+	setSafeMemberProperties(&plan, &updated)
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete removes the member from the safe.
+func (r *safeMemberResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var currState safeMemberModel
+	diags := req.State.Get(ctx, &currState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	mode, err := resolveDeletionMode(currState.DeletionMode, r.deletionMode)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("deletion_mode"), "Invalid deletion mode", err.Error())
+		return
+	}
+
+	err = removeVaultObject(ctx, r.client, mode, safeMemberPath(currState.SafeName.ValueString(), currState.Member.ValueString()))
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to remove safe member",
+			fmt.Sprintf("Removing member [%s] from safe [%s] failed.", currState.Member.ValueString(), currState.SafeName.ValueString()),
+			err,
+		))
+		return
+	}
+
+}
+
+// ImportState imports an existing safe member by safe_name/member.
+func (r *safeMemberResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	// Safe names cannot contain a slash, so the first slash separates the safe from the member
+	safe_name, member, ok := strings.Cut(req.ID, "/")
+	if !ok || safe_name == "" || member == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID in the form safe_name/member, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("safe_name"), safe_name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("member"), member)...)
+}
+
+// safeMembersPath returns the Safe Members API path of a safe.
+func safeMembersPath(safeName string) string {
+	return "Safes/" + url.PathEscape(safeName) + "/Members"
+}
+
+// safeMemberPath returns the Safe Members API path of a single member.
+func safeMemberPath(safeName string, member string) string {
+	return safeMembersPath(safeName) + "/" + url.PathEscape(member)
+}
+
+// membershipExpiration converts the configured expiration date to the unix time expected by the vault.
+func membershipExpiration(value htypes.String) (*int64, diag.Diagnostics) {
+
+	var diags diag.Diagnostics
+
+	if value.IsNull() || value.IsUnknown() {
+		return nil, diags
+	}
+
+	expires, err := time.Parse(time.RFC3339, value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("membership_expiration_date"), "Invalid timestamp", fmt.Sprintf("Expected an RFC3339 timestamp: %s", err.Error()))
+		return nil, diags
+	}
+
+	unix := expires.Unix()
+
+	return &unix, diags
+}
+
+// setSafeMemberProperties copies the member properties returned by the vault into the terraform model.
+// search_in is not returned by the vault and is kept from configuration.
+func setSafeMemberProperties(model *safeMemberModel, member *vaultSafeMember) {
+
+	// Member names are case insensitive in the vault, keep the configured casing so it does not force a replacement
+	if member.Name != nil && !strings.EqualFold(model.Member.ValueString(), *member.Name) {
+		model.Member = htypes.StringValue(*member.Name)
+	}
+
+	// Vault reports member types capitalized, keep the configured casing to avoid a permanent diff
+	if member.Type != nil && !strings.EqualFold(model.MemberType.ValueString(), *member.Type) {
+		model.MemberType = htypes.StringValue(*member.Type)
+	}
+
+	if model.MemberType.IsUnknown() {
+		model.MemberType = htypes.StringNull()
+	}
+
+	if member.ExpirationDate == nil {
+		model.ExpirationDate = htypes.StringNull()
+	} else {
+		// Keep the configured timestamp when it is the same instant written in another format or zone
+		configured, err := time.Parse(time.RFC3339, model.ExpirationDate.ValueString())
+		if err != nil || configured.Unix() != *member.ExpirationDate {
+			model.ExpirationDate = htypes.StringValue(time.Unix(*member.ExpirationDate, 0).UTC().Format(time.RFC3339))
+		}
+	}
+
+	if member.Permissions != nil {
+		model.Permissions = newMemberPermissionsModel(member.Permissions)
+	}
+}
@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"testing"
+
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSetSafeMemberPropertiesNames(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		member     htypes.String
+		memberType htypes.String
+		vaultName  string
+		vaultType  string
+		expMember  string
+		expType    string
+	}{
+		{"configured casing is kept", htypes.StringValue("appteam"), htypes.StringValue("group"), "AppTeam", "Group", "appteam", "group"},
+		{"renamed member is read", htypes.StringValue("AppTeam"), htypes.StringValue("group"), "OpsTeam", "Group", "OpsTeam", "group"},
+		{"changed member type is read", htypes.StringValue("AppTeam"), htypes.StringValue("user"), "AppTeam", "Group", "AppTeam", "Group"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			model := safeMemberModel{Member: tt.member, MemberType: tt.memberType, ExpirationDate: htypes.StringNull()}
+
+			setSafeMemberProperties(&model, &vaultSafeMember{Name: testString(tt.vaultName), Type: testString(tt.vaultType)})
+
+			if model.Member.ValueString() != tt.expMember {
+				t.Errorf("expected member %s, got %s", tt.expMember, model.Member)
+			}
+
+			if model.MemberType.ValueString() != tt.expType {
+				t.Errorf("expected member_type %s, got %s", tt.expType, model.MemberType)
+			}
+		})
+	}
+}
@@ -3,13 +3,19 @@ package provider
 import (
 	"context"
 	"fmt"
-	"log"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
-	cybrapi "github.com/aharriscybr/cybr-api"
 	cybrtypes "github.com/aharriscybr/cybr-api/pkg/cybr/types"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	htypes "github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -18,6 +24,8 @@ import (
 var (
 	_ resource.Resource              = &safeObjectResource{}
 	_ resource.ResourceWithConfigure = &safeObjectResource{}
+	_ resource.ResourceWithImportState = &safeObjectResource{}
+	_ resource.ResourceWithModifyPlan = &safeObjectResource{}
 )
 
 // NewSafeResource is a helper function to simplify the provider implementation.
@@ -27,7 +35,8 @@ func NewSafeResource() resource.Resource {
 
 // msAccountResource is the resource implementation.
 type safeObjectResource struct {
-	client *cybrapi.Client
+	client vaultClient
+	deletionMode string
 }
 
 // Metadata returns the resource type name.
@@ -49,6 +58,8 @@ type safeObjectModel struct {
 	SeedMember htypes.String `tfsdk:"member"`
 	SeedMType htypes.String `tfsdk:"member_type"`
 	PermType htypes.String `tfsdk:"permission_level"`
+	Permissions *memberPermissionsModel `tfsdk:"permissions"`
+	DeletionMode htypes.String `tfsdk:"deletion_mode"`
 }
 
 func (r *safeObjectResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -58,53 +69,93 @@ func (r *safeObjectResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"id": schema.StringAttribute{
 				Description: "CyberArk Privilege Cloud Safe URL ID- Generated from CyberArk after onboarding safe.",
 				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"id_number": schema.Int64Attribute{
 				Description: "CyberArk Privilege Cloud Safe ID- Generated from CyberArk after onboarding safe.",
 				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
 			"last_updated": schema.StringAttribute{
 				Computed: true,
 			},
+			"deletion_mode": deletionModeAttribute(),
 			"safe_name": schema.StringAttribute{
-				Description: "The unique name of the Safe. The following characters cannot be used in the Safe name: \\ / : * < > . | ? “% & +",
+				Description: "The unique name of the Safe, up to 28 characters. The following characters cannot be used in the Safe name: \\ / : * < > . | ? \" % & +",
 				Required: true,
+				Validators: safeNameValidators(),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"member": schema.StringAttribute{
-				Description: "Owning Safe Member.",
+				Description: "Owning Safe Member. Changing the member of an existing safe adds the new member and removes the previous one.",
 				Required: true,
 			},
 			"member_type": schema.StringAttribute{
 				Description: "Member user type: user or group.",
 				Required: true,
+				Validators: memberTypeValidators("user", "group"),
 			},
 			"permission_level": schema.StringAttribute{
-				Description: "Membership Permission Level. Currently supported inputs: full, read, approver, manager.",
+				Description: permissionLevelDescription(),
 				Required: true,
+				Validators: permissionLevelValidators(),
+			},
+			"permissions": schema.SingleNestedAttribute{
+				Description: "Effective permissions granted to the seed member. Permissions set here override permission_level. Changing the permissions updates the seed member in place.",
+				Optional: true,
+				Computed: true,
+				Attributes: memberPermissionsAttributes(),
 			},
 			"safe_desc": schema.StringAttribute{
-				Description: "The description of the Safe.",
+				Description: "The description of the Safe. Removing it clears the description in the vault.",
 				Optional: true,
 			},
 			"safe_loc": schema.StringAttribute{
-				Description: "The location of the Safe in the Vault.",
+				Description: "The location of the Safe in the Vault. Changing the location forces a new Safe.",
 				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"cpm_name": schema.StringAttribute{
 				Description: "The name of the CPM user who will manage the new Safe.",
 				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"retention": schema.Int64Attribute{
 				Description: "The number of retained versions of every password that is stored in the Safe.",
 				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
 			"retention_versions": schema.Int64Attribute{
 				Description: "The number of days that password versions are saved in the Safe.",
 				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
 			"purge": schema.BoolAttribute{
 				Description: "Whether or not to automatically purge files after the end of the Object History Retention Period defined in the Safe properties.",
 				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
 		},
 	}
@@ -116,18 +167,19 @@ func (r *safeObjectResource) Configure(_ context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*cybrapi.Client)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *cybrapi.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = data.Client
+	r.deletionMode = data.DeletionMode
 }
 
 // Create a new resource.
@@ -141,8 +193,15 @@ func (r *safeObjectResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
+	// Effective seed member permissions are expanded from permission_level during plan
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("permissions"), &plan.Permissions)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Strings
-	var cpm_name, safe_name, safe_desc, safe_loc, member, member_type, permission_level string
+	var cpm_name, safe_name, safe_desc, safe_loc, member, member_type string
 
 	// Boolean
 	var purge bool
@@ -165,21 +224,15 @@ func (r *safeObjectResource) Create(ctx context.Context, req resource.CreateRequ
 		member_type = plan.SeedMType.ValueString()
 	}
 
-	if !plan.PermType.IsNull() {
-		if ( plan.PermType.ValueString() == "full" || plan.PermType.ValueString() == "read" || plan.PermType.ValueString() == "approver" || plan.PermType.ValueString() == "manager" ) { 
-			permission_level = plan.PermType.ValueString()
-		} else {
-			tflog.Error(ctx, "Permission level does not match acceptable values.")
-		}
-		
+	seedMember := vaultSafeMember {
+		Name: &member,
+		Type: &member_type,
+		Permissions: plan.Permissions.vaultPermission(),
 	}
 
-	// Required attributes met
+	// Required attributes met, seed member is added once the safe exists
 	newSafe := cybrtypes.SafeData {
 		Name: &safe_name,
-		Owner: &member,
-		OwnerType: &member_type,
-		Level: &permission_level,
 	}
 
 	// Processing optionals
@@ -215,27 +268,44 @@ func (r *safeObjectResource) Create(ctx context.Context, req resource.CreateRequ
 
 
 
-	create, err := cybrapi.CreateSafe(&newSafe, r.client.AuthToken, r.client.Domain)
+	var create cybrtypes.SafeData
+
+	err := vaultRequest(ctx, r.client, http.MethodPost, "Safes", &newSafe, &create)
 	if err != nil {
-		log.Fatal(err)
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to onboard safe",
+			fmt.Sprintf("Onboarding safe [%s] failed.", safe_name),
+			err,
+		))
+		return
 	}
 
-	if create == nil {
-
-		tflog.Error(ctx, "Error catching ID, this means provisioning failed in pipeline. Please check debug logs and try again.")
+	if create.URLID == nil || *create.URLID == "" {
+		resp.Diagnostics.AddError(
+			"Unable to onboard safe",
+			fmt.Sprintf("The vault accepted safe [%s] but did not return a safe URL ID. Please check debug logs and try again.", safe_name),
+		)
+		return
+	}
 
-	} else {
+	setSafeProperties(&plan, &create)
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
 
-		plan.ID = htypes.StringValue(*create.URLID)
-		plan.IDNUM = htypes.Int64Value(*create.NUMBER)
-		plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
-	
-		// Set state to fully populated data
-		resp.State.Set(ctx, plan)
+	// Set state to fully populated data
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 
+	err = vaultRequest(ctx, r.client, http.MethodPost, "Safes/"+*create.URLID+"/Members", &seedMember, nil)
+	if err != nil {
+		// Safe is kept in state so terraform marks it tainted and replaces it on the next apply
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to add seed member to safe",
+			fmt.Sprintf("Safe [%s] was created but adding member [%s] failed. The safe has been marked as tainted and will be replaced on the next apply.", safe_name, member),
+			err,
+		))
+		return
 	}
 
-	
+	tflog.Info(ctx, "Onboarded safe", map[string]interface{}{"safe": safe_name, "member": member})
 }
 
 // Refresh Existing State
@@ -248,37 +318,335 @@ func (r *safeObjectResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	newState, err := cybrapi.GetSafe(currState.ID.ValueStringPointer(), r.client.AuthToken, r.client.Domain)
+	var newState cybrtypes.SafeData
+
+	err := vaultRequest(ctx, r.client, http.MethodGet, "Safes/"+currState.ID.ValueString(), nil, &newState)
 	if err != nil {
-		tflog.Error(ctx, "Unable to retrieve state from CyberArk API")
+		// Objects removed outside of terraform are dropped from state so they are planned for creation
+		if isNotFound(err) {
+			tflog.Warn(ctx, "Safe no longer exists in the vault, removing from state.", map[string]interface{}{"id": currState.ID.ValueString()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to read safe",
+			fmt.Sprintf("Retrieving safe [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
 	}
 
 	tflog.Info(ctx, "Refreshing state")
 
-	// Main Refresh Body
-	currState.Name = htypes.StringValue(*newState.Name)
-	currState.Description = htypes.StringValue(*newState.Description)
-	currState.CPM = htypes.StringValue(*newState.CPM)
-	currState.Location = htypes.StringValue(*newState.Location)
-	currState.RetentionDays = htypes.Int64Value(*newState.RetentionDays)
-	currState.PurgeEnabled = htypes.BoolValue(*newState.PurgeEnabled)
-	
-	// // Set last updated time to last refreshed time
-	currState.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
+	setSafeProperties(&currState, &newState)
+
+	// Safes imported by safe name alone have no seed member to refresh until one is added from configuration
+	if !currState.SeedMember.IsNull() {
+		var member vaultSafeMember
+
+		err = vaultRequest(ctx, r.client, http.MethodGet, "Safes/"+currState.ID.ValueString()+"/Members/"+url.PathEscape(currState.SeedMember.ValueString()), nil, &member)
+		switch {
+		case isNotFound(err):
+			// Members removed outside of terraform are dropped from state so they are added again
+			tflog.Warn(ctx, "Seed member no longer exists in the safe, removing from state.", map[string]interface{}{"id": currState.ID.ValueString(), "member": currState.SeedMember.ValueString()})
+			currState.SeedMember = htypes.StringNull()
+			currState.SeedMType = htypes.StringNull()
+			currState.Permissions = nil
+		case err != nil:
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to read seed member of safe",
+				fmt.Sprintf("Retrieving member [%s] of safe [%s] from the vault failed.", currState.SeedMember.ValueString(), currState.ID.ValueString()),
+				err,
+			))
+			return
+		default:
+			setSeedMemberProperties(&currState, &member)
+		}
+	}
 
-	// Ensure ID is consistent
-	currState.ID = htypes.StringValue(*newState.URLID)
-	currState.IDNUM = htypes.Int64Value(*newState.NUMBER)
+	// Set last updated time to last refreshed time
+	currState.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
 
+	resp.Diagnostics.Append(resp.State.Set(ctx, currState)...)
 }
 
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *safeObjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	tflog.Error(ctx, "Update is not supported through terraform. Please consult with your CyberArk Administrator to process account property updates.")
+
+	var plan, currState safeObjectModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &currState)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	safe_name := plan.Name.ValueString()
+
+	// Name is required by the Safes PUT API even though it cannot be changed in place
+	updSafe := cybrtypes.SafeData {
+		Name: &safe_name,
+	}
+
+	// Description is always sent so removing it from configuration clears it in the vault
+	safe_desc := plan.Description.ValueString()
+	updSafe.Description = &safe_desc
+
+	// Unknown values are left out of the request so the vault keeps its current setting, safe_loc forces a new safe
+
+	if !plan.CPM.IsNull() && !plan.CPM.IsUnknown() {
+		updSafe.CPM = plan.CPM.ValueStringPointer()
+	}
+
+	if !plan.PurgeEnabled.IsNull() && !plan.PurgeEnabled.IsUnknown() {
+		updSafe.PurgeEnabled = plan.PurgeEnabled.ValueBoolPointer()
+	}
+
+	if !plan.RetentionDays.IsNull() && !plan.RetentionDays.IsUnknown() {
+		updSafe.RetentionDays = plan.RetentionDays.ValueInt64Pointer()
+	}
+
+	if !plan.RetentionVersions.IsNull() && !plan.RetentionVersions.IsUnknown() {
+		updSafe.RetentionVersions = plan.RetentionVersions.ValueInt64Pointer()
+	}
+
+	var updated cybrtypes.SafeData
+
+	err := vaultRequest(ctx, r.client, http.MethodPut, "Safes/"+currState.ID.ValueString(), &updSafe, &updated)
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to update safe",
+			fmt.Sprintf("Updating safe [%s] failed.", safe_name),
+			err,
+		))
+		return
+	}
+
+	tflog.Info(ctx, "Updated safe properties", map[string]interface{}{"safe": safe_name})
+
+	// Vault response is the source of truth for the updated properties
+	plan.ID = currState.ID
+	plan.IDNUM = currState.IDNUM
+	setSafeProperties(&plan, &updated)
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
+
+	if plan.SeedMember.Equal(currState.SeedMember) && plan.SeedMType.Equal(currState.SeedMType) && (plan.Permissions == nil || plan.Permissions.equal(currState.Permissions)) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		return
+	}
+
+	// Record the updated safe properties before the seed member is changed, so a failure only retries the member
+	planned := plan
+	plan.SeedMember = currState.SeedMember
+	plan.SeedMType = currState.SeedMType
+	plan.PermType = currState.PermType
+	plan.Permissions = currState.Permissions
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err = updateSeedMember(ctx, r.client, currState.ID.ValueString(), &currState, &planned)
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to update seed member of safe",
+			fmt.Sprintf("Safe [%s] was updated but changing member [%s] to [%s] failed.", safe_name, currState.SeedMember.ValueString(), planned.SeedMember.ValueString()),
+			err,
+		))
+		return
+	}
+
+	tflog.Info(ctx, "Updated safe seed member", map[string]interface{}{"safe": safe_name, "member": planned.SeedMember.ValueString()})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, planned)...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *safeObjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	tflog.Info(ctx, "Delete is not supported through terraform. Please consult with your CyberArk Administrator to process deleting this resource.")
-}
\ No newline at end of file
+
+	var currState safeObjectModel
+	diags := req.State.Get(ctx, &currState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	mode, err := resolveDeletionMode(currState.DeletionMode, r.deletionMode)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("deletion_mode"), "Invalid deletion mode", err.Error())
+		return
+	}
+
+	err = removeVaultObject(ctx, r.client, mode, "Safes/"+currState.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to delete safe",
+			fmt.Sprintf("Deleting safe [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
+	}
+
+}
+
+// ModifyPlan expands permission_level and the permission overrides into the effective seed member permissions.
+// Changed permissions are applied to the seed member in Update.
+func (r *safeObjectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	planMemberPermissions(ctx, req, resp)
+}
+
+// ImportState imports an existing safe by its safe URL ID or safe name, optionally followed by a slash and the seed member.
+// Without a seed member the configured one is added, or updated when it already is a member, on the next apply.
+func (r *safeObjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	// Safe names cannot contain a slash, so the first slash separates the safe from the member
+	id, member, hasMember := strings.Cut(req.ID, "/")
+	if id == "" || hasMember && member == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID in the form safe or safe/member, got: %q", req.ID),
+		)
+		return
+	}
+
+	// Safe URL IDs are the URL encoded safe name, normalize both forms before reading the safe
+	if unescaped, err := url.PathUnescape(id); err == nil {
+		id = unescaped
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), url.PathEscape(id))...)
+
+	if hasMember {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("member"), member)...)
+	}
+}
+
+// updateSeedMember applies a changed seed member through the Safe Members API instead of replacing the safe.
+// A different member is added before the previous one is removed, so the safe always keeps an owner.
+// Without a previous member, the planned member is looked up first and only added when the safe does not have it yet.
+func updateSeedMember(ctx context.Context, c vaultClient, safeID string, current *safeObjectModel, planned *safeObjectModel) error {
+
+	membersPath := "Safes/" + safeID + "/Members"
+	member := planned.SeedMember.ValueString()
+	previous := current.SeedMember.ValueString()
+
+	updMember := vaultSafeMember{
+		Permissions: planned.Permissions.vaultPermission(),
+	}
+
+	if current.SeedMember.IsNull() {
+		err := vaultRequest(ctx, c, http.MethodGet, membersPath+"/"+url.PathEscape(member), nil, nil)
+		if err == nil {
+			return vaultRequest(ctx, c, http.MethodPut, membersPath+"/"+url.PathEscape(member), &updMember, nil)
+		}
+
+		if !isNotFound(err) {
+			return err
+		}
+	}
+
+	// Member names are unique in a safe, the same member only has its permissions updated
+	if !current.SeedMember.IsNull() && strings.EqualFold(member, previous) {
+		return vaultRequest(ctx, c, http.MethodPut, membersPath+"/"+url.PathEscape(member), &updMember, nil)
+	}
+
+	member_type := planned.SeedMType.ValueString()
+
+	newMember := vaultSafeMember{
+		Name: &member,
+		Type: &member_type,
+		Permissions: planned.Permissions.vaultPermission(),
+	}
+
+	err := vaultRequest(ctx, c, http.MethodPost, membersPath, &newMember, nil)
+	if err != nil || current.SeedMember.IsNull() {
+		return err
+	}
+
+	err = vaultRequest(ctx, c, http.MethodDelete, membersPath+"/"+url.PathEscape(previous), nil, nil)
+	if isNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// setSeedMemberProperties copies the seed member returned by the Safe Members API into the terraform model.
+// The configured spelling of the member name and type is kept when it only differs in case from the vault.
+func setSeedMemberProperties(model *safeObjectModel, member *vaultSafeMember) {
+
+	if member.Name != nil && !strings.EqualFold(model.SeedMember.ValueString(), *member.Name) {
+		model.SeedMember = htypes.StringValue(*member.Name)
+	}
+
+	// Vault reports member types capitalized, imported members use the lowercase form of the configuration
+	if member.Type != nil && !strings.EqualFold(model.SeedMType.ValueString(), *member.Type) {
+		model.SeedMType = htypes.StringValue(strings.ToLower(*member.Type))
+	}
+
+	if member.Permissions == nil {
+		return
+	}
+
+	model.Permissions = newMemberPermissionsModel(member.Permissions)
+
+	// permission_level is not stored in the vault, imported members are matched against the presets
+	if model.PermType.IsNull() {
+		if level, ok := permissionPresetOf(member.Permissions); ok {
+			model.PermType = htypes.StringValue(level)
+		}
+	}
+}
+
+// setSafeProperties copies the safe properties returned by the vault into the terraform model.
+// Properties missing from the vault response are left untouched.
+func setSafeProperties(model *safeObjectModel, safe *cybrtypes.SafeData) {
+
+	if safe.URLID != nil {
+		model.ID = htypes.StringValue(*safe.URLID)
+	}
+
+	if safe.NUMBER != nil {
+		model.IDNUM = htypes.Int64Value(*safe.NUMBER)
+	}
+
+	if safe.Name != nil {
+		model.Name = htypes.StringValue(*safe.Name)
+	}
+
+	// Vault reports an empty description when none is set
+	if safe.Description != nil {
+		model.Description = stringValue(safe.Description)
+	}
+
+	// Keep a configured location written differently from the vault, which reports the root location as a single backslash
+	if safe.Location != nil && (model.Location.IsNull() || model.Location.IsUnknown() || !strings.EqualFold(normalizeSafeLocation(model.Location.ValueString()), normalizeSafeLocation(*safe.Location))) {
+		model.Location = htypes.StringValue(*safe.Location)
+	}
+
+	if safe.CPM != nil {
+		model.CPM = htypes.StringValue(*safe.CPM)
+	}
+
+	if safe.RetentionDays != nil {
+		model.RetentionDays = htypes.Int64Value(*safe.RetentionDays)
+	}
+
+	if safe.RetentionVersions != nil {
+		model.RetentionVersions = htypes.Int64Value(*safe.RetentionVersions)
+	}
+
+	if safe.PurgeEnabled != nil {
+		model.PurgeEnabled = htypes.BoolValue(*safe.PurgeEnabled)
+	}
+}
+
+// normalizeSafeLocation returns a safe location as a single backslash separated path from the root, empty and repeated backslashes are both the root.
+func normalizeSafeLocation(location string) string {
+	parts := strings.FieldsFunc(location, func(r rune) bool { return r == '\\' })
+	return "\\" + strings.Join(parts, "\\")
+}
@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cybrtypes "github.com/aharriscybr/cybr-api/pkg/cybr/types"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSetSafePropertiesLocation(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		configured htypes.String
+		vault      string
+		expected   htypes.String
+	}{
+		{"unset location is read", htypes.StringNull(), `\`, htypes.StringValue(`\`)},
+		{"unknown location is read", htypes.StringUnknown(), `\Finance`, htypes.StringValue(`\Finance`)},
+		{"root written as double backslash is kept", htypes.StringValue(`\\`), `\`, htypes.StringValue(`\\`)},
+		{"empty root is kept", htypes.StringValue(""), `\`, htypes.StringValue("")},
+		{"trailing backslash and case are kept", htypes.StringValue(`\finance\`), `\Finance`, htypes.StringValue(`\finance\`)},
+		{"location changed outside terraform is read", htypes.StringValue(`\Finance`), `\Operations`, htypes.StringValue(`\Operations`)},
+		{"location moved from the root is read", htypes.StringValue(`\`), `\Finance`, htypes.StringValue(`\Finance`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			model := safeObjectModel{Location: tt.configured}
+			location := tt.vault
+
+			setSafeProperties(&model, &cybrtypes.SafeData{Location: &location})
+
+			if !model.Location.Equal(tt.expected) {
+				t.Errorf("expected %s, got %s", tt.expected, model.Location)
+			}
+		})
+	}
+}
+
+func TestSetSeedMemberProperties(t *testing.T) {
+
+	read := permissionPresets["read"]
+	custom := permissionPresets["read"]
+	custom.ViewAuditLog = true
+
+	tests := []struct {
+		name        string
+		model       safeObjectModel
+		member      vaultSafeMember
+		memberName  string
+		memberType  string
+		level       htypes.String
+		permissions cybrtypes.Permission
+	}{
+		{
+			name:        "imported member matching a preset",
+			model:       safeObjectModel{SeedMember: htypes.StringValue("AppOwners"), SeedMType: htypes.StringNull(), PermType: htypes.StringNull()},
+			member:      vaultSafeMember{Name: testString("AppOwners"), Type: testString("Group"), Permissions: &read},
+			memberName:  "AppOwners",
+			memberType:  "group",
+			level:       htypes.StringValue("read"),
+			permissions: read,
+		},
+		{
+			name:        "imported member with custom permissions",
+			model:       safeObjectModel{SeedMember: htypes.StringValue("AppOwners"), SeedMType: htypes.StringNull(), PermType: htypes.StringNull()},
+			member:      vaultSafeMember{Name: testString("AppOwners"), Type: testString("User"), Permissions: &custom},
+			memberName:  "AppOwners",
+			memberType:  "user",
+			level:       htypes.StringNull(),
+			permissions: custom,
+		},
+		{
+			name:        "configured spelling is kept",
+			model:       safeObjectModel{SeedMember: htypes.StringValue("appowners"), SeedMType: htypes.StringValue("GROUP"), PermType: htypes.StringValue("full")},
+			member:      vaultSafeMember{Name: testString("AppOwners"), Type: testString("Group"), Permissions: &read},
+			memberName:  "appowners",
+			memberType:  "GROUP",
+			level:       htypes.StringValue("full"),
+			permissions: read,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			model := tt.model
+			setSeedMemberProperties(&model, &tt.member)
+
+			if model.SeedMember.ValueString() != tt.memberName {
+				t.Errorf("expected member %s, got %s", tt.memberName, model.SeedMember)
+			}
+
+			if model.SeedMType.ValueString() != tt.memberType {
+				t.Errorf("expected member_type %s, got %s", tt.memberType, model.SeedMType)
+			}
+
+			if !model.PermType.Equal(tt.level) {
+				t.Errorf("expected permission_level %s, got %s", tt.level, model.PermType)
+			}
+
+			if *model.Permissions.vaultPermission() != tt.permissions {
+				t.Errorf("expected permissions %+v, got %+v", tt.permissions, *model.Permissions.vaultPermission())
+			}
+		})
+	}
+}
+
+func testString(value string) *string {
+	return &value
+}
+
+// testSafeMembers is an httptest Safe Members API of a single safe, recording the requests it received.
+type testSafeMembers struct {
+	mu       sync.Mutex
+	members  map[string]bool
+	requests []string
+}
+
+func (v *testSafeMembers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	member := strings.TrimPrefix(r.URL.Path, "/Safes/GEN_BY_TF_abc/Members")
+	member = strings.TrimPrefix(member, "/")
+	v.requests = append(v.requests, strings.TrimSpace(r.Method+" "+member))
+
+	switch r.Method {
+	case http.MethodPost:
+		var added vaultSafeMember
+		_ = json.NewDecoder(r.Body).Decode(&added)
+		v.members[*added.Name] = true
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet, http.MethodPut:
+		if !v.members[member] {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case http.MethodDelete:
+		delete(v.members, member)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestUpdateSeedMember(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		existing []string
+		previous htypes.String
+		planned  string
+		requests []string
+	}{
+		{"permissions of the same member are updated", []string{"AppOwners"}, htypes.StringValue("appowners"), "AppOwners", []string{"PUT AppOwners"}},
+		{"new member is added before the previous one is removed", []string{"AppOwners"}, htypes.StringValue("AppOwners"), "Operators", []string{"POST", "DELETE AppOwners"}},
+		{"unrecorded member already in the safe is updated", []string{"AppOwners"}, htypes.StringNull(), "AppOwners", []string{"GET AppOwners", "PUT AppOwners"}},
+		{"unrecorded member missing from the safe is added", nil, htypes.StringNull(), "AppOwners", []string{"GET AppOwners", "POST"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			vault := &testSafeMembers{members: map[string]bool{}}
+			for _, member := range tt.existing {
+				vault.members[member] = true
+			}
+
+			server := httptest.NewServer(vault)
+			defer server.Close()
+
+			calls := 0
+			client := newAPIClient(&http.Client{}, server.URL+"/", "Bearer ", newTokenManager(testTokens(time.Hour, &calls, "token")))
+
+			current := safeObjectModel{SeedMember: tt.previous}
+			planned := safeObjectModel{SeedMember: htypes.StringValue(tt.planned), SeedMType: htypes.StringValue("group")}
+
+			if err := updateSeedMember(context.Background(), client, "GEN_BY_TF_abc", &current, &planned); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if strings.Join(vault.requests, ",") != strings.Join(tt.requests, ",") {
+				t.Errorf("expected requests %v, got %v", tt.requests, vault.requests)
+			}
+
+			if !vault.members[tt.planned] {
+				t.Errorf("expected %s to be a member of the safe", tt.planned)
+			}
+		})
+	}
+}
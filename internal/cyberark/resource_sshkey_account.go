@@ -0,0 +1,525 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"time"
+
+	cybrtypes "github.com/aharriscybr/cybr-api/pkg/cybr/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &sshKeyAccountResource{}
+	_ resource.ResourceWithConfigure = &sshKeyAccountResource{}
+	_ resource.ResourceWithImportState = &sshKeyAccountResource{}
+	_ resource.ResourceWithConfigValidators = &sshKeyAccountResource{}
+	_ resource.ResourceWithModifyPlan = &sshKeyAccountResource{}
+)
+
+// NewSSHKeyAccountResource is a helper function to simplify the provider implementation.
+func NewSSHKeyAccountResource() resource.Resource {
+	return &sshKeyAccountResource{}
+}
+
+// sshKeyAccountResource is the resource implementation.
+type sshKeyAccountResource struct {
+	client vaultClient
+	deletionMode string
+}
+
+// Metadata returns the resource type name.
+func (r *sshKeyAccountResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sshkeyaccount"
+}
+
+type sshKeyCredModel struct {
+
+	Name 		htypes.String `tfsdk:"name"`
+	Address 	htypes.String `tfsdk:"address"`
+	Username 	htypes.String `tfsdk:"username"`
+	Platform 	htypes.String `tfsdk:"platform"`
+	Safe 		htypes.String `tfsdk:"safe"`
+	SecretType 	htypes.String `tfsdk:"secrettype"`
+	PrivateKey	htypes.String `tfsdk:"private_key"`
+	PrivateKeyFile	htypes.String `tfsdk:"private_key_file"`
+	Fingerprint	htypes.String `tfsdk:"public_key_fingerprint"`
+	ID 			htypes.String `tfsdk:"id"`
+	LastUpdated htypes.String `tfsdk:"last_updated"`
+	Manage 		htypes.Bool `tfsdk:"sm_manage"`
+	ManageReason	htypes.String `tfsdk:"sm_manage_reason"`
+	DeletionMode htypes.String `tfsdk:"deletion_mode"`
+}
+
+func (r *sshKeyAccountResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+
+	resp.Schema = schema.Schema{
+		Description: "Unix SSH Key Account Resource",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "CyberArk Privilege Cloud Credential ID- Generated from CyberArk after onboarding account into a safe.",
+				Computed: true,
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"deletion_mode": deletionModeAttribute(),
+			"name": schema.StringAttribute{
+				Description: "Custom Account Name for customizing the object name in a safe.",
+				Required: true,
+			},
+			"address": schema.StringAttribute{
+				Description: "Hostname or IP of the Unix machine.",
+				Required: true,
+			},
+			"username": schema.StringAttribute{
+				Description: "Username of the Credential object.",
+				Required: true,
+			},
+			"platform": schema.StringAttribute{
+				Description: "Management Platform associated with the SSH Key Credential. Defaults to UnixSSHKeys. Changing the platform forces a new account.",
+				Optional: true,
+				Computed: true,
+				Default: stringdefault.StaticString("UnixSSHKeys"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"safe": schema.StringAttribute{
+				Description: "Target Safe where the credential object will be onboarded. Changing the safe forces a new account.",
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"secrettype": schema.StringAttribute{
+				Description: "Secret type of credential, SSH keys are always onboarded as key.",
+				Optional: true,
+				Computed: true,
+				Default: stringdefault.StaticString("key"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("key"),
+				},
+			},
+			"private_key": schema.StringAttribute{
+				Description: "Unencrypted PEM or OpenSSH private key. Conflicts with private_key_file.",
+				Optional: true,
+				Sensitive: true,
+				Validators: []validator.String{
+					sshPrivateKeyValidator{},
+				},
+			},
+			"private_key_file": schema.StringAttribute{
+				Description: "Path to a file containing an unencrypted PEM or OpenSSH private key. The key is read during plan and apply and is not stored in state. Conflicts with private_key.",
+				Optional: true,
+			},
+			"public_key_fingerprint": schema.StringAttribute{
+				Description: "SHA256 fingerprint of the public key derived from the private key, in the format used by ssh-keygen -l. Recomputed during plan, so replacing the contents of private_key_file shows as a key rotation.",
+				Computed: true,
+			},
+			"sm_manage": schema.BoolAttribute{
+				Description: "Automatic Management of a credential. Optional Value.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sm_manage_reason": schema.StringAttribute{
+				Description: "If sm_manage is false, provide reason why credential is not managed.",
+				Optional: true,
+			},
+		},
+	}
+}
+
+// ConfigValidators validate attribute combinations before plan.
+func (r *sshKeyAccountResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		manualManagementReasonValidator{},
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("private_key"),
+			path.MatchRoot("private_key_file"),
+		),
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *sshKeyAccountResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = data.Client
+	r.deletionMode = data.DeletionMode
+}
+
+// ModifyPlan reads and parses the private key so an invalid key fails the plan and the planned fingerprint shows key rotations.
+func (r *sshKeyAccountResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+
+	// Nothing to plan when the resource is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var key, keyFile htypes.String
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("private_key"), &key)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("private_key_file"), &keyFile)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Keys only known at apply time are fingerprinted then, missing keys are reported by the config validators
+	if key.IsUnknown() || keyFile.IsUnknown() || (key.IsNull() && keyFile.IsNull()) {
+		return
+	}
+
+	fingerprint, keyPath, err := loadSSHKeyFingerprint(key, keyFile)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(keyPath, "Invalid private key", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("public_key_fingerprint"), fingerprint)...)
+}
+
+// Create a new resource.
+func (r *sshKeyAccountResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var plan sshKeyCredModel
+	var sm_props cybrtypes.SecretManagement
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var name, address, username, platform, safe, secrettype, secret, sm_manage_reason string
+
+	var sm_manage bool
+
+	name = os.Getenv("CYBERARK_ACCOUNT_CUSTOM_NAME")
+	address = os.Getenv("CYBERARK_ACCOUNT_ADDRESS")
+	username = os.Getenv("CYBERARK_ACCOUNT_USERNAME")
+	safe = os.Getenv("CYBERARK_ACCOUNT_SAFE")
+
+	// Not processing env variable overrides for optional fields, this must be defined in the terraform plan.
+	// This is a design decision.
+	if !plan.Name.IsNull() {
+		name = plan.Name.ValueString()
+	}
+
+	if !plan.Address.IsNull() {
+		address = plan.Address.ValueString()
+	}
+
+	if !plan.Username.IsNull() {
+		username = plan.Username.ValueString()
+	}
+
+	if !plan.Safe.IsNull() {
+		safe = plan.Safe.ValueString()
+	}
+
+	platform = plan.Platform.ValueString()
+	secrettype = plan.SecretType.ValueString()
+
+	// Key files are read again at apply time, the planned fingerprint guards against the file changing in between
+	secret, fingerprint, keyPath, err := loadSSHKey(plan.PrivateKey, plan.PrivateKeyFile)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(keyPath, "Invalid private key", err.Error())
+		return
+	}
+
+	if !plan.Fingerprint.IsUnknown() && plan.Fingerprint.ValueString() != fingerprint {
+		resp.Diagnostics.AddAttributeError(
+			keyPath,
+			"Private key changed after plan",
+			fmt.Sprintf("The planned key fingerprint was %s but the key read during apply has fingerprint %s. Run terraform plan again.", plan.Fingerprint.ValueString(), fingerprint),
+		)
+		return
+	}
+
+	if !plan.Manage.IsNull() && !plan.Manage.IsUnknown() {
+		sm_manage = plan.Manage.ValueBool()
+		sm_props.AutomaticManagement = &sm_manage
+	}
+
+	if !plan.ManageReason.IsNull() {
+		sm_manage_reason = plan.ManageReason.ValueString()
+		sm_props.ManualManagementReason = &sm_manage_reason
+	}
+
+	newAccount := cybrtypes.Credential {
+		Name: &name,
+		Address: &address,
+		UserName: &username,
+		Platform: &platform,
+		SafeName: &safe,
+		SecretType: &secrettype,
+		Secret: &secret,
+		Props: &cybrtypes.AccountProps{},
+		SecretMgmt: &sm_props,
+	}
+
+	create, err := createVaultAccount(ctx, r.client, &newAccount)
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to onboard account",
+			fmt.Sprintf("Onboarding account [%s] into safe [%s] failed.", name, safe),
+			err,
+		))
+		return
+	}
+
+	plan.ID = htypes.StringValue(create)
+	plan.Fingerprint = htypes.StringValue(fingerprint)
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
+
+	if plan.Manage.IsUnknown() {
+		plan.Manage = htypes.BoolNull()
+	}
+
+	// Set state to fully populated data
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Refresh Existing State
+func (r *sshKeyAccountResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var currState sshKeyCredModel
+	diags := req.State.Get(ctx, &currState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var newState cybrtypes.CredentialResponse
+
+	err := vaultRequest(ctx, r.client, http.MethodGet, "Accounts/"+url.PathEscape(currState.ID.ValueString()), nil, &newState)
+	if err != nil {
+		// Objects removed outside of terraform are dropped from state so they are planned for creation
+		if isNotFound(err) {
+			tflog.Warn(ctx, "Account no longer exists in the vault, removing from state.", map[string]interface{}{"id": currState.ID.ValueString()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to read account",
+			fmt.Sprintf("Retrieving account [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
+	}
+
+	tflog.Info(ctx, "Refreshing state")
+
+	setSSHKeyAccountProperties(&currState, &newState)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, currState)...)
+}
+
+
+// Update patches the changed account properties and uploads a rotated private key to the vault.
+func (r *sshKeyAccountResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan, currState sshKeyCredModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &currState)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := currState.ID.ValueString()
+
+	// Key files are read again at apply time, the planned fingerprint guards against the file changing in between
+	secret, fingerprint, keyPath, err := loadSSHKey(plan.PrivateKey, plan.PrivateKeyFile)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(keyPath, "Invalid private key", err.Error())
+		return
+	}
+
+	if !plan.Fingerprint.IsUnknown() && plan.Fingerprint.ValueString() != fingerprint {
+		resp.Diagnostics.AddAttributeError(
+			keyPath,
+			"Private key changed after plan",
+			fmt.Sprintf("The planned key fingerprint was %s but the key read during apply has fingerprint %s. Run terraform plan again.", plan.Fingerprint.ValueString(), fingerprint),
+		)
+		return
+	}
+
+	var patch vaultAccountPatch
+
+	patch.setString("/name", plan.Name, currState.Name)
+	patch.setString("/address", plan.Address, currState.Address)
+	patch.setString("/userName", plan.Username, currState.Username)
+	patch.setBool("/secretManagement/automaticManagementEnabled", plan.Manage, currState.Manage)
+	patch.setString("/secretManagement/manualManagementReason", plan.ManageReason, currState.ManageReason)
+
+	if len(patch) > 0 {
+		err = patchVaultAccount(ctx, r.client, id, patch)
+		if err != nil {
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to update account",
+				fmt.Sprintf("Updating the properties of account [%s] failed.", id),
+				err,
+			))
+			return
+		}
+
+		tflog.Info(ctx, "Updated account properties", map[string]interface{}{"id": id})
+	}
+
+	plan.ID = currState.ID
+	plan.Fingerprint = htypes.StringValue(fingerprint)
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
+
+	if plan.Manage.IsUnknown() {
+		plan.Manage = currState.Manage
+	}
+
+	// Keys cannot be read back from the vault, record the configured key after an import without uploading it
+	if !currState.Fingerprint.IsNull() && currState.Fingerprint.ValueString() != fingerprint {
+
+		err = updateVaultSecret(ctx, r.client, id, secret)
+		if err != nil {
+			// Record the updated properties so only the key is uploaded again
+			plan.PrivateKey = currState.PrivateKey
+			plan.PrivateKeyFile = currState.PrivateKeyFile
+			plan.Fingerprint = currState.Fingerprint
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to update private key",
+				fmt.Sprintf("Uploading the rotated private key of account [%s] failed.", id),
+				err,
+			))
+			return
+		}
+
+		tflog.Info(ctx, "Updated account private key", map[string]interface{}{"id": id, "fingerprint": fingerprint})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *sshKeyAccountResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var currState sshKeyCredModel
+	diags := req.State.Get(ctx, &currState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	mode, err := resolveDeletionMode(currState.DeletionMode, r.deletionMode)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("deletion_mode"), "Invalid deletion mode", err.Error())
+		return
+	}
+
+	err = removeVaultObject(ctx, r.client, mode, "Accounts/"+url.PathEscape(currState.ID.ValueString()))
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to delete account",
+			fmt.Sprintf("Deleting account [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
+	}
+
+}
+
+// ImportState imports an existing vault account by its account ID.
+func (r *sshKeyAccountResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// loadSSHKey reads the configured private key and returns it with its public key fingerprint.
+// The returned path points at the attribute the key was read from for error reporting.
+func loadSSHKey(value htypes.String, file htypes.String) (string, string, path.Path, error) {
+
+	keyPath := path.Root("private_key")
+	if value.IsNull() {
+		keyPath = path.Root("private_key_file")
+	}
+
+	key, err := readPrivateKey(value, file)
+	if err != nil {
+		return "", "", keyPath, err
+	}
+
+	fingerprint, err := sshPublicKeyFingerprint(key)
+	if err != nil {
+		return "", "", keyPath, err
+	}
+
+	return key, fingerprint, keyPath, nil
+}
+
+// loadSSHKeyFingerprint returns the public key fingerprint of the configured private key.
+func loadSSHKeyFingerprint(value htypes.String, file htypes.String) (string, path.Path, error) {
+	_, fingerprint, keyPath, err := loadSSHKey(value, file)
+	return fingerprint, keyPath, err
+}
+
+// setSSHKeyAccountProperties copies the SSH key account returned by the vault into the terraform model.
+// The private key is never returned by the vault and is left untouched.
+func setSSHKeyAccountProperties(model *sshKeyCredModel, account *cybrtypes.CredentialResponse) {
+
+	if account.CredID != nil {
+		model.ID = htypes.StringValue(*account.CredID)
+	}
+
+	model.Name = stringValue(account.Name)
+	model.Address = stringValue(account.Address)
+	model.Username = stringValue(account.UserName)
+	model.Platform = stringValue(account.Platform)
+	model.Safe = stringValue(account.SafeName)
+	model.SecretType = stringValue(account.SecretType)
+
+	if account.SecretMgmt != nil {
+		model.Manage = boolValue(account.SecretMgmt.AutomaticManagement)
+		model.ManageReason = stringValue(account.SecretMgmt.ManualManagementReason)
+
+		// Set last updated time to last updated time in the vault
+		if account.SecretMgmt.ModifiedTime != nil {
+			newTime := time.Unix(*account.SecretMgmt.ModifiedTime, 0)
+			model.LastUpdated = htypes.StringValue(newTime.Format(time.RFC850))
+		}
+	}
+}
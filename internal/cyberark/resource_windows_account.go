@@ -0,0 +1,607 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	cybrtypes "github.com/aharriscybr/cybr-api/pkg/cybr/types"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &windowsAccountResource{}
+	_ resource.ResourceWithConfigure = &windowsAccountResource{}
+	_ resource.ResourceWithImportState = &windowsAccountResource{}
+	_ resource.ResourceWithConfigValidators = &windowsAccountResource{}
+)
+
+// NewWindowsAccountResource is a helper function to simplify the provider implementation.
+func NewWindowsAccountResource() resource.Resource {
+	return &windowsAccountResource{}
+}
+
+// windowsAccountResource is the resource implementation.
+type windowsAccountResource struct {
+	client vaultClient
+	deletionMode string
+}
+
+// Metadata returns the resource type name.
+func (r *windowsAccountResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_windowsaccount"
+}
+
+type windowsCredModel struct {
+
+	Name 		htypes.String `tfsdk:"name"`
+	Address 	htypes.String `tfsdk:"address"`
+	Username 	htypes.String `tfsdk:"username"`
+	Platform 	htypes.String `tfsdk:"platform"`
+	Safe 		htypes.String `tfsdk:"safe"`
+	SecretType 	htypes.String `tfsdk:"secrettype"`
+	Secret 		htypes.String `tfsdk:"secret"`
+	ID 			htypes.String `tfsdk:"id"`
+	LastUpdated htypes.String `tfsdk:"last_updated"`
+	LogonDomain	htypes.String `tfsdk:"logon_domain"`
+	LogonAccount	*linkedAccountModel `tfsdk:"logon_account"`
+	ReconcileAccount	*linkedAccountModel `tfsdk:"reconcile_account"`
+	Manage 		htypes.Bool `tfsdk:"sm_manage"`
+	ManageReason	htypes.String `tfsdk:"sm_manage_reason"`
+	DeletionMode htypes.String `tfsdk:"deletion_mode"`
+}
+
+// linkedAccountModel identifies an account linked as a logon or reconcile account.
+type linkedAccountModel struct {
+	Safe	htypes.String `tfsdk:"safe"`
+	Name	htypes.String `tfsdk:"name"`
+	Folder	htypes.String `tfsdk:"folder"`
+}
+
+// linkedAccountAttribute returns the schema of a linked logon or reconcile account.
+func linkedAccountAttribute(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: description,
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			"safe": schema.StringAttribute{
+				Description: "Safe of the linked account.",
+				Required: true,
+			},
+			"name": schema.StringAttribute{
+				Description: "Account name of the linked account, for example the name of a cyberarkoss_windowsaccount.",
+				Required: true,
+			},
+			"folder": schema.StringAttribute{
+				Description: "Folder of the linked account in its safe. Defaults to Root.",
+				Optional: true,
+				Computed: true,
+				Default: stringdefault.StaticString("Root"),
+			},
+		},
+	}
+}
+
+func (r *windowsAccountResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+
+	resp.Schema = schema.Schema{
+		Description: "Windows Domain and Local Account Resource",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "CyberArk Privilege Cloud Credential ID- Generated from CyberArk after onboarding account into a safe.",
+				Computed: true,
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"deletion_mode": deletionModeAttribute(),
+			"name": schema.StringAttribute{
+				Description: "Custom Account Name for customizing the object name in a safe.",
+				Required: true,
+			},
+			"address": schema.StringAttribute{
+				Description: "Fully qualified domain name for domain accounts, or the hostname or IP of the server for local accounts.",
+				Required: true,
+			},
+			"username": schema.StringAttribute{
+				Description: "Username of the Credential object.",
+				Required: true,
+			},
+			"platform": schema.StringAttribute{
+				Description: "Management Platform associated with the Windows Credential, for example WinDomain or WinServerLocal. Defaults to WinDomain. Changing the platform forces a new account.",
+				Optional: true,
+				Computed: true,
+				Default: stringdefault.StaticString("WinDomain"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"safe": schema.StringAttribute{
+				Description: "Target Safe where the credential object will be onboarded. Changing the safe forces a new account.",
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"secrettype": schema.StringAttribute{
+				Description: "Secret type of credential. Defaults to password. Changing the secret type forces a new account.",
+				Optional: true,
+				Computed: true,
+				Default: stringdefault.StaticString("password"),
+				Validators: secretTypeValidators(),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"secret": schema.StringAttribute{
+				Description: "Password of the credential object.",
+				Required: true,
+				Sensitive: true,
+			},
+			"logon_domain": schema.StringAttribute{
+				Description: "NetBIOS name of the domain used to log on with domain accounts.",
+				Optional: true,
+			},
+			"logon_account": linkedAccountAttribute("Account used by the CPM to log on to the machine before changing the password of this account."),
+			"reconcile_account": linkedAccountAttribute("Account used by the CPM to reconcile the password of this account."),
+			"sm_manage": schema.BoolAttribute{
+				Description: "Automatic Management of a credential. Optional Value.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sm_manage_reason": schema.StringAttribute{
+				Description: "If sm_manage is false, provide reason why credential is not managed.",
+				Optional: true,
+			},
+		},
+	}
+}
+
+// ConfigValidators validate attribute combinations before plan.
+func (r *windowsAccountResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		manualManagementReasonValidator{},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *windowsAccountResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = data.Client
+	r.deletionMode = data.DeletionMode
+}
+
+// Create a new resource.
+func (r *windowsAccountResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var plan windowsCredModel
+	var sm_props cybrtypes.SecretManagement
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var name, address, username, platform, safe, secrettype, secret, sm_manage_reason string
+
+	var sm_manage bool
+
+	name = os.Getenv("CYBERARK_ACCOUNT_CUSTOM_NAME")
+	address = os.Getenv("CYBERARK_ACCOUNT_ADDRESS")
+	username = os.Getenv("CYBERARK_ACCOUNT_USERNAME")
+	safe = os.Getenv("CYBERARK_ACCOUNT_SAFE")
+	secret = os.Getenv("CYBERARK_ACCOUNT_SECRET")
+
+	// Not processing env variable overrides for optional fields, this must be defined in the terraform plan.
+	// This is a design decision.
+	if !plan.Name.IsNull() {
+		name = plan.Name.ValueString()
+	}
+
+	if !plan.Address.IsNull() {
+		address = plan.Address.ValueString()
+	}
+
+	if !plan.Username.IsNull() {
+		username = plan.Username.ValueString()
+	}
+
+	if !plan.Safe.IsNull() {
+		safe = plan.Safe.ValueString()
+	}
+
+	if !plan.Secret.IsNull() {
+		secret = plan.Secret.ValueString()
+	}
+
+	platform = plan.Platform.ValueString()
+	secrettype = plan.SecretType.ValueString()
+
+	if !plan.Manage.IsNull() && !plan.Manage.IsUnknown() {
+		sm_manage = plan.Manage.ValueBool()
+		sm_props.AutomaticManagement = &sm_manage
+	}
+
+	if !plan.ManageReason.IsNull() {
+		sm_manage_reason = plan.ManageReason.ValueString()
+		sm_props.ManualManagementReason = &sm_manage_reason
+	}
+
+	newAccount := vaultAccountRequest {
+		Name: &name,
+		Address: &address,
+		UserName: &username,
+		Platform: &platform,
+		SafeName: &safe,
+		SecretType: &secrettype,
+		Secret: &secret,
+		SecretMgmt: &sm_props,
+	}
+
+	if !plan.LogonDomain.IsNull() {
+		newAccount.PlatformProps = map[string]string{"LogonDomain": plan.LogonDomain.ValueString()}
+	}
+
+	create, err := createVaultAccount(ctx, r.client, &newAccount)
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to onboard account",
+			fmt.Sprintf("Onboarding account [%s] into safe [%s] failed.", name, safe),
+			err,
+		))
+		return
+	}
+
+	plan.ID = htypes.StringValue(create)
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
+
+	if plan.Manage.IsUnknown() {
+		plan.Manage = htypes.BoolNull()
+	}
+
+	// Set state to fully populated data
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+
+	// Account is kept in state so terraform marks it tainted and replaces it on the next apply when linking fails
+	for _, link := range []struct {
+		index int
+		account *linkedAccountModel
+		attribute string
+	}{
+		{linkedLogonAccount, plan.LogonAccount, "logon_account"},
+		{linkedReconcileAccount, plan.ReconcileAccount, "reconcile_account"},
+	} {
+		if link.account == nil {
+			continue
+		}
+
+		err = linkVaultAccount(ctx, r.client, create, link.account.vaultLink(link.index))
+		if err != nil {
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to link account",
+				fmt.Sprintf("Account [%s] was onboarded but linking %s [%s] failed. The account has been marked as tainted and will be replaced on the next apply.", name, link.attribute, link.account.Name.ValueString()),
+				err,
+			))
+			return
+		}
+	}
+}
+
+// Refresh Existing State
+func (r *windowsAccountResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var currState windowsCredModel
+	diags := req.State.Get(ctx, &currState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var newState vaultAccount
+
+	err := vaultRequest(ctx, r.client, http.MethodGet, "Accounts/"+url.PathEscape(currState.ID.ValueString()), nil, &newState)
+	if err != nil {
+		// Objects removed outside of terraform are dropped from state so they are planned for creation
+		if isNotFound(err) {
+			tflog.Warn(ctx, "Account no longer exists in the vault, removing from state.", map[string]interface{}{"id": currState.ID.ValueString()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to read account",
+			fmt.Sprintf("Retrieving account [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
+	}
+
+	tflog.Info(ctx, "Refreshing state")
+
+	setWindowsAccountProperties(&currState, &newState)
+
+	links, err := readLinkedVaultAccounts(ctx, r.client, currState.ID.ValueString())
+	switch {
+	case isNotFound(err):
+		// Vaults without the linked accounts API keep the recorded links
+		tflog.Warn(ctx, "Linked accounts cannot be read from the vault, keeping the recorded links.", map[string]interface{}{"id": currState.ID.ValueString()})
+	case err != nil:
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to read linked accounts",
+			fmt.Sprintf("Retrieving the linked accounts of account [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
+	default:
+		currState.LogonAccount = linkedAccountAt(links, linkedLogonAccount, currState.LogonAccount)
+		currState.ReconcileAccount = linkedAccountAt(links, linkedReconcileAccount, currState.ReconcileAccount)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, currState)...)
+}
+
+
+// Update patches the changed account properties, updates the secret and relinks changed logon and reconcile accounts.
+func (r *windowsAccountResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan, currState windowsCredModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &currState)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := currState.ID.ValueString()
+
+	var patch vaultAccountPatch
+
+	patch.setString("/name", plan.Name, currState.Name)
+	patch.setString("/address", plan.Address, currState.Address)
+	patch.setString("/userName", plan.Username, currState.Username)
+	patch.setString("/platformAccountProperties/LogonDomain", plan.LogonDomain, currState.LogonDomain)
+	patch.setBool("/secretManagement/automaticManagementEnabled", plan.Manage, currState.Manage)
+	patch.setString("/secretManagement/manualManagementReason", plan.ManageReason, currState.ManageReason)
+
+	if len(patch) > 0 {
+		err := patchVaultAccount(ctx, r.client, id, patch)
+		if err != nil {
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to update account",
+				fmt.Sprintf("Updating the properties of account [%s] failed.", id),
+				err,
+			))
+			return
+		}
+
+		tflog.Info(ctx, "Updated account properties", map[string]interface{}{"id": id})
+	}
+
+	plan.ID = currState.ID
+	plan.LastUpdated = htypes.StringValue(time.Now().Format(time.RFC850))
+
+	if plan.Manage.IsUnknown() {
+		plan.Manage = currState.Manage
+	}
+
+	// Secrets cannot be read back from the vault, record the configured secret after an import without updating it
+	if !currState.Secret.IsNull() && !plan.Secret.Equal(currState.Secret) {
+
+		err := updateVaultSecret(ctx, r.client, id, plan.Secret.ValueString())
+		if err != nil {
+			// Record the updated properties so only the secret and the links are updated again
+			plan.Secret = currState.Secret
+			plan.LogonAccount = currState.LogonAccount
+			plan.ReconcileAccount = currState.ReconcileAccount
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to update account secret",
+				fmt.Sprintf("Updating the secret of account [%s] failed.", id),
+				err,
+			))
+			return
+		}
+
+		tflog.Info(ctx, "Updated account secret", map[string]interface{}{"id": id})
+	}
+
+	links := []struct {
+		index int
+		planned **linkedAccountModel
+		current *linkedAccountModel
+		attribute string
+	}{
+		{linkedLogonAccount, &plan.LogonAccount, currState.LogonAccount, "logon_account"},
+		{linkedReconcileAccount, &plan.ReconcileAccount, currState.ReconcileAccount, "reconcile_account"},
+	}
+
+	// Linked accounts are managed through their own API and can be changed in place
+	for i, link := range links {
+		planned := *link.planned
+		if planned.equal(link.current) {
+			continue
+		}
+
+		linked := link.current
+		var err error
+
+		// Remove the previous link first so the index is free for the new account
+		if linked != nil {
+			err = unlinkVaultAccount(ctx, r.client, id, link.index)
+			if err == nil {
+				linked = nil
+			}
+		}
+
+		if err == nil && planned != nil {
+			err = linkVaultAccount(ctx, r.client, id, planned.vaultLink(link.index))
+		}
+
+		if err != nil {
+			// Record what is linked in the vault now, so the next plan only retries the links that were not updated
+			*link.planned = linked
+			for _, rest := range links[i+1:] {
+				*rest.planned = rest.current
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+
+			resp.Diagnostics.Append(vaultDiagnostic(
+				"Unable to update linked account",
+				fmt.Sprintf("Updating %s of account [%s] failed.", link.attribute, id),
+				err,
+			))
+			return
+		}
+
+		tflog.Info(ctx, "Updated linked account", map[string]interface{}{"id": id, "link": link.attribute})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *windowsAccountResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var currState windowsCredModel
+	diags := req.State.Get(ctx, &currState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	mode, err := resolveDeletionMode(currState.DeletionMode, r.deletionMode)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("deletion_mode"), "Invalid deletion mode", err.Error())
+		return
+	}
+
+	err = removeVaultObject(ctx, r.client, mode, "Accounts/"+url.PathEscape(currState.ID.ValueString()))
+	if err != nil {
+		resp.Diagnostics.Append(vaultDiagnostic(
+			"Unable to delete account",
+			fmt.Sprintf("Deleting account [%s] from the vault failed.", currState.ID.ValueString()),
+			err,
+		))
+		return
+	}
+
+}
+
+// ImportState imports an existing vault account by its account ID.
+func (r *windowsAccountResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// vaultLink converts the linked account to the body of the link account API.
+func (m *linkedAccountModel) vaultLink(index int) *vaultLinkedAccount {
+	return &vaultLinkedAccount{
+		Safe: m.Safe.ValueString(),
+		ExtraPasswordIndex: index,
+		Name: m.Name.ValueString(),
+		Folder: m.Folder.ValueString(),
+	}
+}
+
+// equal reports whether both linked accounts point at the same account, nil means not linked.
+func (m *linkedAccountModel) equal(other *linkedAccountModel) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+
+	return m.Safe.Equal(other.Safe) && m.Name.Equal(other.Name) && m.Folder.Equal(other.Folder)
+}
+
+// linkedAccountAt returns the account linked at extraPasswordIndex, or nil when none is linked.
+// The recorded spelling of the safe, name and folder is kept when it only differs in case from the vault.
+func linkedAccountAt(links []vaultLinkedAccountDetails, index int, recorded *linkedAccountModel) *linkedAccountModel {
+
+	for _, link := range links {
+		if link.ExtraPasswordIndex != index {
+			continue
+		}
+
+		folder := link.FolderName
+		if folder == "" {
+			folder = "Root"
+		}
+
+		if recorded != nil && strings.EqualFold(recorded.Safe.ValueString(), link.SafeName) && strings.EqualFold(recorded.Name.ValueString(), link.Name) && strings.EqualFold(recorded.Folder.ValueString(), folder) {
+			return recorded
+		}
+
+		return &linkedAccountModel{
+			Safe: htypes.StringValue(link.SafeName),
+			Name: htypes.StringValue(link.Name),
+			Folder: htypes.StringValue(folder),
+		}
+	}
+
+	return nil
+}
+
+// setWindowsAccountProperties copies the Windows account returned by the vault into the terraform model.
+// The secret is not returned by the vault and is left untouched, linked accounts are read separately.
+func setWindowsAccountProperties(model *windowsCredModel, account *vaultAccount) {
+
+	if account.CredID != nil {
+		model.ID = htypes.StringValue(*account.CredID)
+	}
+
+	model.Name = stringValue(account.Name)
+	model.Address = stringValue(account.Address)
+	model.Username = stringValue(account.UserName)
+	model.Platform = stringValue(account.Platform)
+	model.Safe = stringValue(account.SafeName)
+	model.SecretType = stringValue(account.SecretType)
+
+	if account.SecretMgmt != nil {
+		model.Manage = boolValue(account.SecretMgmt.AutomaticManagement)
+		model.ManageReason = stringValue(account.SecretMgmt.ManualManagementReason)
+
+		// Set last updated time to last updated time in the vault
+		if account.SecretMgmt.ModifiedTime != nil {
+			newTime := time.Unix(*account.SecretMgmt.ModifiedTime, 0)
+			model.LastUpdated = htypes.StringValue(newTime.Format(time.RFC850))
+		}
+	}
+
+	model.LogonDomain = htypes.StringNull()
+
+	if domain, ok := account.PlatformProps["LogonDomain"]; ok {
+		model.LogonDomain = htypes.StringValue(fmt.Sprint(domain))
+	}
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"testing"
+
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// testLinkedAccount returns a linked account model.
+func testLinkedAccount(safe string, name string, folder string) *linkedAccountModel {
+	return &linkedAccountModel{
+		Safe:   htypes.StringValue(safe),
+		Name:   htypes.StringValue(name),
+		Folder: htypes.StringValue(folder),
+	}
+}
+
+func TestLinkedAccountAt(t *testing.T) {
+
+	links := []vaultLinkedAccountDetails{
+		{Name: "Logon-Admin", SafeName: "Windows", FolderName: "Root", ExtraPasswordIndex: linkedLogonAccount},
+		{Name: "Reconcile-Admin", SafeName: "Windows", ExtraPasswordIndex: linkedReconcileAccount},
+	}
+
+	tests := []struct {
+		name     string
+		links    []vaultLinkedAccountDetails
+		index    int
+		recorded *linkedAccountModel
+		expected *linkedAccountModel
+	}{
+		{"imported link is read", links, linkedLogonAccount, nil, testLinkedAccount("Windows", "Logon-Admin", "Root")},
+		{"missing folder is the root folder", links, linkedReconcileAccount, nil, testLinkedAccount("Windows", "Reconcile-Admin", "Root")},
+		{"recorded spelling is kept", links, linkedLogonAccount, testLinkedAccount("windows", "logon-admin", "root"), testLinkedAccount("windows", "logon-admin", "root")},
+		{"link changed outside terraform is read", links, linkedLogonAccount, testLinkedAccount("Windows", "Other-Admin", "Root"), testLinkedAccount("Windows", "Logon-Admin", "Root")},
+		{"link removed outside terraform", nil, linkedLogonAccount, testLinkedAccount("Windows", "Logon-Admin", "Root"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := linkedAccountAt(tt.links, tt.index, tt.recorded); !actual.equal(tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, actual)
+			}
+		})
+	}
+}
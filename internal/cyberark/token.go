@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// tokenRefreshWindow is how long before expiry a token is replaced, so calls in flight never carry an expired token.
+	tokenRefreshWindow = 2 * time.Minute
+	// tokenDefaultLifetime is assumed when the identity platform does not report expires_in.
+	tokenDefaultLifetime = 15 * time.Minute
+)
+
+// tokenFetcher requests a new token and returns it with its lifetime.
+type tokenFetcher func(ctx context.Context) (string, time.Duration, error)
+
+// tokenManager caches the bearer token of the provider and refreshes it before it expires.
+// It is shared by every resource and safe for concurrent use, only one refresh runs at a time.
+type tokenManager struct {
+	mu     sync.Mutex
+	fetch  tokenFetcher
+	token  string
+	expiry time.Time
+	now    func() time.Time
+}
+
+func newTokenManager(fetch tokenFetcher) *tokenManager {
+	return &tokenManager{
+		fetch: fetch,
+		now:   time.Now,
+	}
+}
+
+// Token returns a token that is valid for at least the refresh window, requesting a new one when needed.
+func (m *tokenManager) Token(ctx context.Context) (string, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.token != "" && m.now().Before(m.expiry.Add(-tokenRefreshWindow)) {
+		return m.token, nil
+	}
+
+	token, lifetime, err := m.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if lifetime <= 0 {
+		lifetime = tokenDefaultLifetime
+	}
+
+	m.token = token
+	m.expiry = m.now().Add(lifetime)
+
+	tflog.Debug(ctx, "Refreshed vault API token", map[string]interface{}{"expiry": m.expiry.Format(time.RFC3339)})
+
+	return m.token, nil
+}
+
+// Invalidate discards token so the next call requests a new one.
+// A token that was already replaced by another caller is left alone.
+func (m *tokenManager) Invalidate(token string) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.token == token {
+		m.token = ""
+	}
+}
+
+// tokenTransport authorizes every vault API request with the current token.
+// A request rejected with 401 is sent once more with a new token, since the token can be revoked before it expires.
+// scheme prefixes the token in the Authorization header, PVWA session tokens are sent without one.
+type tokenTransport struct {
+	base   http.RoundTripper
+	tokens *tokenManager
+	scheme string
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	token, err := t.tokens.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(authorizeRequest(req, t.scheme+token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// Requests whose body cannot be replayed are returned as is
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	tflog.Debug(req.Context(), "Vault API rejected the token, retrying with a new token", map[string]interface{}{"path": req.URL.Path})
+
+	t.tokens.Invalidate(token)
+
+	token, err = t.tokens.Token(req.Context())
+	if err != nil {
+		tflog.Warn(req.Context(), "Unable to renew the vault API token", map[string]interface{}{"error": err.Error()})
+		return resp, nil
+	}
+
+	retry := authorizeRequest(req, t.scheme+token)
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	return t.base.RoundTrip(retry)
+}
+
+// authorizeRequest returns a copy of req carrying the Authorization header, round trippers must not modify the original request.
+func authorizeRequest(req *http.Request, authorization string) *http.Request {
+	authorized := req.Clone(req.Context())
+	authorized.Header.Set("Authorization", authorization)
+	return authorized
+}
+
+// identityError is returned when the identity platform rejects a token request.
+type identityError struct {
+	StatusCode  int
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *identityError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("identity platform returned %d %s: %s", e.StatusCode, e.Code, e.Description)
+	}
+
+	return fmt.Sprintf("identity platform returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// identityToken is the token response of the identity platform.
+type identityToken struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// identityTokenFetcher requests platform tokens from a CyberArk Identity tenant with the client credentials grant.
+func identityTokenFetcher(hc *http.Client, tenant string, clientID string, clientSecret string) tokenFetcher {
+
+	return func(ctx context.Context) (string, time.Duration, error) {
+
+		form := url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {clientID},
+			"client_secret": {clientSecret},
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+tenant+".id.cyberark.cloud/oauth2/platformtoken", strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", 0, fmt.Errorf("unable to construct token request: %w", err)
+		}
+
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		tflog.Debug(ctx, "Requesting identity platform token", map[string]interface{}{"tenant": tenant, "clientid": clientID})
+
+		return doTokenRequest(hc, req)
+	}
+}
+
+// doTokenRequest sends an OAuth2 token request and decodes the token and its lifetime.
+func doTokenRequest(hc *http.Client, req *http.Request) (string, time.Duration, error) {
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to reach identity platform: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to read identity platform response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		iErr := &identityError{}
+
+		// Error bodies are best effort, keep the status code if the body cannot be decoded
+		_ = json.Unmarshal(body, iErr)
+		iErr.StatusCode = resp.StatusCode
+
+		return "", 0, iErr
+	}
+
+	var token identityToken
+
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", 0, fmt.Errorf("unable to decode identity platform response: %w", err)
+	}
+
+	if token.AccessToken == "" {
+		return "", 0, errors.New("the identity platform did not return an access token")
+	}
+
+	return token.AccessToken, time.Duration(token.ExpiresIn) * time.Second, nil
+}
@@ -0,0 +1,285 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testTokens returns a fetcher handing out the given tokens in order, each valid for lifetime.
+// The number of fetches is recorded in calls.
+func testTokens(lifetime time.Duration, calls *int, tokens ...string) tokenFetcher {
+
+	var mu sync.Mutex
+
+	return func(_ context.Context) (string, time.Duration, error) {
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if *calls >= len(tokens) {
+			return "", 0, errors.New("no more tokens")
+		}
+
+		token := tokens[*calls]
+		*calls++
+
+		return token, lifetime, nil
+	}
+}
+
+func TestTokenManagerRefresh(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		lifetime time.Duration
+		elapsed  time.Duration
+		expected string
+		fetches  int
+	}{
+		{"cached token is reused", 10 * time.Minute, time.Minute, "first", 1},
+		{"token is refreshed inside the refresh window", 10 * time.Minute, 10*time.Minute - tokenRefreshWindow, "second", 2},
+		{"expired token is refreshed", 10 * time.Minute, 11 * time.Minute, "second", 2},
+		{"default lifetime without expires_in", 0, tokenDefaultLifetime - tokenRefreshWindow - time.Second, "first", 1},
+		{"default lifetime is refreshed", 0, tokenDefaultLifetime - tokenRefreshWindow, "second", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+			calls := 0
+
+			m := newTokenManager(testTokens(tt.lifetime, &calls, "first", "second"))
+			m.now = func() time.Time { return now }
+
+			if _, err := m.Token(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			now = now.Add(tt.elapsed)
+
+			token, err := m.Token(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if token != tt.expected {
+				t.Errorf("expected token %s, got %s", tt.expected, token)
+			}
+
+			if calls != tt.fetches {
+				t.Errorf("expected %d fetches, got %d", tt.fetches, calls)
+			}
+		})
+	}
+}
+
+func TestTokenManagerInvalidate(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		invalidated string
+		expected    string
+	}{
+		{"current token is discarded", "first", "second"},
+		{"replaced token is ignored", "previous", "first"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			calls := 0
+			m := newTokenManager(testTokens(time.Hour, &calls, "first", "second"))
+
+			if _, err := m.Token(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			m.Invalidate(tt.invalidated)
+
+			token, err := m.Token(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if token != tt.expected {
+				t.Errorf("expected token %s, got %s", tt.expected, token)
+			}
+		})
+	}
+}
+
+// testVault is an httptest vault accepting a single token, or every token when accepted is empty, and recording what it received.
+type testVault struct {
+	mu             sync.Mutex
+	accepted       string
+	authorizations []string
+	bodies         []string
+}
+
+func (v *testVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	body, _ := io.ReadAll(r.Body)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.authorizations = append(v.authorizations, r.Header.Get("Authorization"))
+	v.bodies = append(v.bodies, string(body))
+
+	if v.accepted != "" && r.Header.Get("Authorization") != v.accepted {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestTokenTransportRetry(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		scheme         string
+		accepted       string
+		method         string
+		body           string
+		status         int
+		authorizations []string
+	}{
+		{
+			name:           "valid token is sent once",
+			scheme:         "Bearer ",
+			accepted:       "Bearer first",
+			method:         http.MethodGet,
+			status:         http.StatusOK,
+			authorizations: []string{"Bearer first"},
+		},
+		{
+			name:           "revoked token is retried with a new token",
+			scheme:         "Bearer ",
+			accepted:       "Bearer second",
+			method:         http.MethodGet,
+			status:         http.StatusOK,
+			authorizations: []string{"Bearer first", "Bearer second"},
+		},
+		{
+			name:           "request body is replayed on retry",
+			scheme:         "",
+			accepted:       "second",
+			method:         http.MethodPost,
+			body:           `{"name":"account"}`,
+			status:         http.StatusOK,
+			authorizations: []string{"first", "second"},
+		},
+		{
+			name:           "retry happens only once",
+			scheme:         "Bearer ",
+			accepted:       "Bearer third",
+			method:         http.MethodGet,
+			status:         http.StatusUnauthorized,
+			authorizations: []string{"Bearer first", "Bearer second"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			vault := &testVault{accepted: tt.accepted}
+			server := httptest.NewServer(vault)
+			defer server.Close()
+
+			calls := 0
+			tokens := newTokenManager(testTokens(time.Hour, &calls, "first", "second", "third"))
+
+			// Clients without a transport fall back to http.DefaultTransport
+			client := newAPIClient(&http.Client{}, server.URL+"/", tt.scheme, tokens)
+
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+
+			req, err := http.NewRequest(tt.method, client.URL("Accounts"), body)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+
+			if strings.Join(vault.authorizations, ",") != strings.Join(tt.authorizations, ",") {
+				t.Errorf("expected authorizations %v, got %v", tt.authorizations, vault.authorizations)
+			}
+
+			for i, received := range vault.bodies {
+				if received != tt.body {
+					t.Errorf("expected request %d to carry body %q, got %q", i, tt.body, received)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenTransportRefreshWindow(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		elapsed  time.Duration
+		expected []string
+	}{
+		{"token outside the refresh window is reused", 5 * time.Minute, []string{"Bearer first", "Bearer first"}},
+		{"token inside the refresh window is replaced before the request", 10*time.Minute - tokenRefreshWindow, []string{"Bearer first", "Bearer second"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			vault := &testVault{}
+			server := httptest.NewServer(vault)
+			defer server.Close()
+
+			now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+			calls := 0
+
+			tokens := newTokenManager(testTokens(10*time.Minute, &calls, "first", "second"))
+			tokens.now = func() time.Time { return now }
+
+			client := newAPIClient(&http.Client{}, server.URL+"/", "Bearer ", tokens)
+
+			for i := 0; i < 2; i++ {
+				if i == 1 {
+					now = now.Add(tt.elapsed)
+				}
+
+				req, err := http.NewRequest(http.MethodGet, client.URL("Safes"), nil)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				resp, err := client.Do(req)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				resp.Body.Close()
+			}
+
+			if strings.Join(vault.authorizations, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected authorizations %v, got %v", tt.expected, vault.authorizations)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"time"
+
+	cybrhttp "github.com/aharriscybr/cybr-api/pkg/cybr/http"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// transportModel holds the provider attributes of the HTTP client used for the identity platform, the PVWA and the vault API.
+type transportModel struct {
+	ProxyURL            htypes.String `tfsdk:"proxy_url"`
+	CACertFile          htypes.String `tfsdk:"ca_cert_file"`
+	CACertPEM           htypes.String `tfsdk:"ca_cert_pem"`
+	InsecureSkipVerify  htypes.Bool   `tfsdk:"insecure_skip_verify"`
+	RequestTimeout      htypes.Int64  `tfsdk:"request_timeout"`
+	MaxIdleConns        htypes.Int64  `tfsdk:"max_idle_conns"`
+	MaxIdleConnsPerHost htypes.Int64  `tfsdk:"max_idle_conns_per_host"`
+	MaxConnsPerHost     htypes.Int64  `tfsdk:"max_conns_per_host"`
+}
+
+// transportAttributes configure the HTTP client built in Configure.
+func transportAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"proxy_url": schema.StringAttribute{
+			Description: "URL of the proxy used for every request, for example http://proxy.example.com:3128. Defaults to the proxy set in the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.",
+			Optional:    true,
+			Validators: []validator.String{
+				stringvalidator.RegexMatches(regexp.MustCompile(`^(https?|socks5)://[^/]+`), "must be an http, https or socks5 URL"),
+			},
+		},
+		"ca_cert_file": schema.StringAttribute{
+			Description: "Path to a PEM file of additional certificate authorities to trust, for example the CA of a TLS inspecting proxy or of a self-hosted PVWA. Conflicts with ca_cert_pem.",
+			Optional:    true,
+		},
+		"ca_cert_pem": schema.StringAttribute{
+			Description: "PEM encoded additional certificate authorities to trust. Conflicts with ca_cert_file.",
+			Optional:    true,
+		},
+		"insecure_skip_verify": schema.BoolAttribute{
+			Description: "Disable TLS certificate verification. Only intended for test environments, prefer ca_cert_file or ca_cert_pem. Defaults to false.",
+			Optional:    true,
+		},
+		"request_timeout": schema.Int64Attribute{
+			Description: "Time limit in seconds for each request, including connecting, redirects and reading the response. Defaults to no limit.",
+			Optional:    true,
+			Validators: []validator.Int64{
+				int64validator.AtLeast(1),
+			},
+		},
+		"max_idle_conns": schema.Int64Attribute{
+			Description: "Maximum number of idle connections kept open across all hosts. Defaults to 100.",
+			Optional:    true,
+			Validators: []validator.Int64{
+				int64validator.AtLeast(0),
+			},
+		},
+		"max_idle_conns_per_host": schema.Int64Attribute{
+			Description: "Maximum number of idle connections kept open per host. Defaults to 2.",
+			Optional:    true,
+			Validators: []validator.Int64{
+				int64validator.AtLeast(0),
+			},
+		},
+		"max_conns_per_host": schema.Int64Attribute{
+			Description: "Maximum number of connections per host, including active ones. Requests wait for a free connection when the limit is reached. Defaults to no limit.",
+			Optional:    true,
+			Validators: []validator.Int64{
+				int64validator.AtLeast(0),
+			},
+		},
+	}
+}
+
+// newHTTPClient returns the cybr-api HTTP client with the configured transport settings applied.
+// Invalid settings are reported with the path of the attribute to fix.
+func newHTTPClient(m *transportModel) (*http.Client, path.Path, error) {
+
+	hc := cybrhttp.GetClient()
+
+	// The cybr-api transport has no dialer timeouts and a handshake timeout too short behind proxies,
+	// start from http.DefaultTransport for its proxy, dial, idle and HTTP/2 defaults instead
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = http.DefaultMaxIdleConnsPerHost
+
+	if !m.ProxyURL.IsNull() {
+		proxy, err := url.Parse(m.ProxyURL.ValueString())
+		if err != nil {
+			return nil, path.Root("proxy_url"), fmt.Errorf("unable to parse proxy_url: %w", err)
+		}
+
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	var pem []byte
+	var pemPath path.Path
+
+	if !m.CACertFile.IsNull() {
+		data, err := os.ReadFile(m.CACertFile.ValueString())
+		if err != nil {
+			return nil, path.Root("ca_cert_file"), fmt.Errorf("unable to read ca_cert_file: %w", err)
+		}
+
+		pem, pemPath = data, path.Root("ca_cert_file")
+	}
+
+	if !m.CACertPEM.IsNull() {
+		pem, pemPath = []byte(m.CACertPEM.ValueString()), path.Root("ca_cert_pem")
+	}
+
+	tlsConfig := &tls.Config{}
+
+	if pem != nil {
+		// Additional authorities are trusted on top of the system ones so public endpoints keep working
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, pemPath, errors.New("no PEM encoded certificate could be parsed")
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	tlsConfig.InsecureSkipVerify = m.InsecureSkipVerify.ValueBool()
+	transport.TLSClientConfig = tlsConfig
+
+	if !m.MaxIdleConns.IsNull() {
+		transport.MaxIdleConns = int(m.MaxIdleConns.ValueInt64())
+	}
+
+	if !m.MaxIdleConnsPerHost.IsNull() {
+		transport.MaxIdleConnsPerHost = int(m.MaxIdleConnsPerHost.ValueInt64())
+	}
+
+	if !m.MaxConnsPerHost.IsNull() {
+		transport.MaxConnsPerHost = int(m.MaxConnsPerHost.ValueInt64())
+	}
+
+	hc.Transport = transport
+
+	if !m.RequestTimeout.IsNull() {
+		hc.Timeout = time.Duration(m.RequestTimeout.ValueInt64()) * time.Second
+	}
+
+	return hc, path.Empty(), nil
+}
@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// testTransportModel returns transport settings with every attribute null, as when none is configured.
+func testTransportModel() transportModel {
+	return transportModel{
+		ProxyURL:            htypes.StringNull(),
+		CACertFile:          htypes.StringNull(),
+		CACertPEM:           htypes.StringNull(),
+		InsecureSkipVerify:  htypes.BoolNull(),
+		RequestTimeout:      htypes.Int64Null(),
+		MaxIdleConns:        htypes.Int64Null(),
+		MaxIdleConnsPerHost: htypes.Int64Null(),
+		MaxConnsPerHost:     htypes.Int64Null(),
+	}
+}
+
+func TestNewHTTPClientDefaults(t *testing.T) {
+
+	m := testTransportModel()
+
+	hc, _, err := newHTTPClient(&m)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	transport := hc.Transport.(*http.Transport)
+
+	if reflect.ValueOf(transport.Proxy).Pointer() != reflect.ValueOf(http.ProxyFromEnvironment).Pointer() {
+		t.Error("expected the proxy to be read from the environment")
+	}
+
+	if transport.DialContext == nil {
+		t.Error("expected the dialer of http.DefaultTransport")
+	}
+
+	for _, setting := range []struct {
+		name     string
+		actual   interface{}
+		expected interface{}
+	}{
+		{"TLSHandshakeTimeout", transport.TLSHandshakeTimeout, 10 * time.Second},
+		{"IdleConnTimeout", transport.IdleConnTimeout, 90 * time.Second},
+		{"ExpectContinueTimeout", transport.ExpectContinueTimeout, time.Second},
+		{"ForceAttemptHTTP2", transport.ForceAttemptHTTP2, true},
+		{"MaxIdleConns", transport.MaxIdleConns, 100},
+		{"MaxIdleConnsPerHost", transport.MaxIdleConnsPerHost, 2},
+		{"MaxConnsPerHost", transport.MaxConnsPerHost, 0},
+		{"Timeout", hc.Timeout, time.Duration(0)},
+	} {
+		if setting.actual != setting.expected {
+			t.Errorf("expected %s %v, got %v", setting.name, setting.expected, setting.actual)
+		}
+	}
+
+	if transport.TLSClientConfig.RootCAs != nil || transport.TLSClientConfig.InsecureSkipVerify {
+		t.Error("expected the system certificate authorities to be verified")
+	}
+}
+
+func TestNewHTTPClientSettings(t *testing.T) {
+
+	m := testTransportModel()
+	m.ProxyURL = htypes.StringValue("http://proxy.example.com:3128")
+	m.RequestTimeout = htypes.Int64Value(30)
+	m.MaxIdleConns = htypes.Int64Value(10)
+	m.MaxIdleConnsPerHost = htypes.Int64Value(5)
+	m.MaxConnsPerHost = htypes.Int64Value(20)
+
+	hc, _, err := newHTTPClient(&m)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	transport := hc.Transport.(*http.Transport)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://pvwa.example.com/PasswordVault/API/Safes", nil)
+
+	proxy, err := transport.Proxy(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if proxy == nil || proxy.String() != "http://proxy.example.com:3128" {
+		t.Errorf("expected proxy http://proxy.example.com:3128, got %v", proxy)
+	}
+
+	for _, setting := range []struct {
+		name     string
+		actual   interface{}
+		expected interface{}
+	}{
+		{"MaxIdleConns", transport.MaxIdleConns, 10},
+		{"MaxIdleConnsPerHost", transport.MaxIdleConnsPerHost, 5},
+		{"MaxConnsPerHost", transport.MaxConnsPerHost, 20},
+		{"Timeout", hc.Timeout, 30 * time.Second},
+	} {
+		if setting.actual != setting.expected {
+			t.Errorf("expected %s %v, got %v", setting.name, setting.expected, setting.actual)
+		}
+	}
+}
+
+func TestNewHTTPClientTLS(t *testing.T) {
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	serverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(serverCA), 0o600); err != nil {
+		t.Fatalf("unable to write CA file: %s", err)
+	}
+
+	tests := []struct {
+		name      string
+		configure func(m *transportModel)
+		err       bool
+		errPath   path.Path
+		reachable bool
+	}{
+		{
+			name:      "unknown authority is rejected",
+			configure: func(_ *transportModel) {},
+		},
+		{
+			name: "ca_cert_pem is trusted",
+			configure: func(m *transportModel) {
+				m.CACertPEM = htypes.StringValue(serverCA)
+			},
+			reachable: true,
+		},
+		{
+			name: "ca_cert_file is trusted",
+			configure: func(m *transportModel) {
+				m.CACertFile = htypes.StringValue(caFile)
+			},
+			reachable: true,
+		},
+		{
+			name: "insecure_skip_verify skips verification",
+			configure: func(m *transportModel) {
+				m.InsecureSkipVerify = htypes.BoolValue(true)
+			},
+			reachable: true,
+		},
+		{
+			name: "invalid ca_cert_pem",
+			configure: func(m *transportModel) {
+				m.CACertPEM = htypes.StringValue("not a certificate")
+			},
+			err:     true,
+			errPath: path.Root("ca_cert_pem"),
+		},
+		{
+			name: "missing ca_cert_file",
+			configure: func(m *transportModel) {
+				m.CACertFile = htypes.StringValue(filepath.Join(t.TempDir(), "missing.pem"))
+			},
+			err:     true,
+			errPath: path.Root("ca_cert_file"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			m := testTransportModel()
+			tt.configure(&m)
+
+			hc, errPath, err := newHTTPClient(&m)
+
+			if tt.err {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				if !errPath.Equal(tt.errPath) {
+					t.Errorf("expected the error on %s, got %s", tt.errPath, errPath)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			resp, err := hc.Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+
+			if reachable := err == nil; reachable != tt.reachable {
+				t.Errorf("expected reachable %t, got %t: %v", tt.reachable, reachable, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// stringValue converts an optional vault string into a terraform value.
+// Empty strings are reported by the vault for unset properties and are treated as null.
+func stringValue(v *string) htypes.String {
+	if v == nil || *v == "" {
+		return htypes.StringNull()
+	}
+
+	return htypes.StringValue(*v)
+}
+
+// int64Value converts an optional vault number into a terraform value.
+func int64Value(v *int64) htypes.Int64 {
+	if v == nil {
+		return htypes.Int64Null()
+	}
+
+	return htypes.Int64Value(*v)
+}
+
+// boolValue converts an optional vault flag into a terraform value.
+func boolValue(v *bool) htypes.Bool {
+	if v == nil {
+		return htypes.BoolNull()
+	}
+
+	return htypes.BoolValue(*v)
+}
+
+// timeValue converts a vault unix timestamp into the RFC850 format used by last_updated.
+func timeValue(v *int64) htypes.String {
+	if v == nil || *v == 0 {
+		return htypes.StringNull()
+	}
+
+	return htypes.StringValue(time.Unix(*v, 0).Format(time.RFC850))
+}
+
+// platformPropertiesValue converts vault platform account properties into a terraform map of strings.
+func platformPropertiesValue(ctx context.Context, props map[string]interface{}) (htypes.Map, diag.Diagnostics) {
+
+	values := make(map[string]string, len(props))
+
+	for k, v := range props {
+		values[k] = fmt.Sprint(v)
+	}
+
+	return htypes.MapValueFrom(ctx, htypes.StringType, values)
+}
+
+// trackedPlatformPropertiesValue refreshes the platform properties already tracked in terraform from the vault.
+// Platforms report default and computed properties that were never configured, so only tracked keys are refreshed.
+func trackedPlatformPropertiesValue(ctx context.Context, current htypes.Map, props map[string]interface{}) (htypes.Map, diag.Diagnostics) {
+
+	if current.IsNull() || current.IsUnknown() {
+		return htypes.MapNull(htypes.StringType), nil
+	}
+
+	tracked := make(map[string]interface{}, len(current.Elements()))
+
+	for k := range current.Elements() {
+		if v, ok := props[k]; ok {
+			tracked[k] = v
+		}
+	}
+
+	return platformPropertiesValue(ctx, tracked)
+}
+
+// platformPropertyValue returns a platform account property as a string, nil when the vault does not report it.
+func platformPropertyValue(props map[string]interface{}, key string) *string {
+
+	v, ok := props[key]
+	if !ok || v == nil {
+		return nil
+	}
+
+	value := fmt.Sprint(v)
+	return &value
+}
@@ -0,0 +1,435 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	cybrtypes "github.com/aharriscybr/cybr-api/pkg/cybr/types"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// vaultError is returned when the vault API answers with a non-success status code.
+// ErrorCode and ErrorMessage map to the error body returned by the PasswordVault API.
+type vaultError struct {
+	StatusCode   int
+	ErrorCode    string `json:"ErrorCode"`
+	ErrorMessage string `json:"ErrorMessage"`
+}
+
+func (e *vaultError) Error() string {
+	if e.ErrorCode == "" {
+		return fmt.Sprintf("vault API returned status %d", e.StatusCode)
+	}
+
+	return fmt.Sprintf("vault API returned status %d [%s]: %s", e.StatusCode, e.ErrorCode, e.ErrorMessage)
+}
+
+// vaultRequest sends a JSON request to the PasswordVault API using the provider configured client.
+// When body is not nil it is marshalled as the request payload, when out is not nil the response is decoded into it.
+func vaultRequest(ctx context.Context, c vaultClient, method string, path string, body interface{}, out interface{}) error {
+
+	var payload io.Reader
+
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("unable to format request body: %w", err)
+		}
+		payload = bytes.NewBuffer(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.URL(path), payload)
+	if err != nil {
+		return fmt.Errorf("unable to construct api request: %w", err)
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+
+	tflog.Debug(ctx, "Sending vault API request", map[string]interface{}{"method": method, "path": path})
+
+	response, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to reach vault API: %w", err)
+	}
+	defer response.Body.Close()
+
+	respBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read vault API response: %w", err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		vErr := &vaultError{StatusCode: response.StatusCode}
+
+		// Vault error bodies are best effort, keep the status code if the body cannot be decoded
+		_ = json.Unmarshal(respBody, vErr)
+		vErr.StatusCode = response.StatusCode
+
+		return vErr
+	}
+
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("unable to decode vault API response: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// isNotFound reports whether err is a vault API response for an object that does not exist.
+func isNotFound(err error) bool {
+	var vErr *vaultError
+	return errors.As(err, &vErr) && vErr.StatusCode == http.StatusNotFound
+}
+
+// vaultDiagnostic converts an error returned while calling the vault into an error diagnostic.
+// The detail includes the HTTP status, the CyberArk error code and a remediation hint when available.
+func vaultDiagnostic(summary string, detail string, err error) diag.Diagnostic {
+
+	var vErr *vaultError
+
+	if !errors.As(err, &vErr) {
+		return diag.NewErrorDiagnostic(summary, fmt.Sprintf("%s\n\nError: %s\n\nHint: Verify the domain or pvwa_url configured on the provider and that the vault can be reached from this host, through proxy_url when one is required.", detail, err.Error()))
+	}
+
+	var msg strings.Builder
+
+	msg.WriteString(detail)
+	msg.WriteString(fmt.Sprintf("\n\nHTTP Status: %d %s", vErr.StatusCode, http.StatusText(vErr.StatusCode)))
+
+	if vErr.ErrorCode != "" {
+		msg.WriteString("\nCyberArk Error Code: " + vErr.ErrorCode)
+	}
+
+	if vErr.ErrorMessage != "" {
+		msg.WriteString("\nCyberArk Error Message: " + vErr.ErrorMessage)
+	}
+
+	if hint := vaultErrorHint(vErr.StatusCode); hint != "" {
+		msg.WriteString("\n\nHint: " + hint)
+	}
+
+	return diag.NewErrorDiagnostic(summary, msg.String())
+}
+
+// vaultErrorHint returns remediation guidance for common vault API status codes.
+func vaultErrorHint(status int) string {
+
+	switch {
+	case status == http.StatusBadRequest:
+		return "The vault rejected the request body. Check the configured values against the platform and safe settings."
+	case status == http.StatusUnauthorized:
+		return "The authentication token was rejected or has expired. Verify the provider credentials and try again."
+	case status == http.StatusForbidden:
+		return "The provider user is not authorized for this operation. Ensure it holds the Privilege Cloud Safe Managers role and the required safe permissions."
+	case status == http.StatusNotFound:
+		return "The object does not exist in the vault. Check the ID, safe name and platform ID."
+	case status == http.StatusConflict:
+		return "An object with the same name already exists. Import it with terraform import or choose a different name."
+	case status == http.StatusTooManyRequests:
+		return "The vault is rate limiting requests. Reduce terraform parallelism and try again."
+	case status >= 500:
+		return "The vault reported an internal error. Try again later and contact your CyberArk Administrator if the issue persists."
+	}
+
+	return ""
+}
+
+// createVaultAccount onboards a credential into its target safe and returns the new account ID.
+func createVaultAccount(ctx context.Context, c vaultClient, account interface{}) (string, error) {
+
+	var created cybrtypes.CredentialResponse
+
+	err := vaultRequest(ctx, c, http.MethodPost, "Accounts", account, &created)
+	if err != nil {
+		return "", err
+	}
+
+	if created.CredID == nil || *created.CredID == "" {
+		return "", errors.New("the vault accepted the account but did not return an account ID")
+	}
+
+	return *created.CredID, nil
+}
+
+// vaultAccount is an account returned by the vault, including every platform account property.
+// Properties are kept as a generic map since each platform defines its own set.
+type vaultAccount struct {
+	cybrtypes.CredentialResponse
+	PlatformProps map[string]interface{} `json:"platformAccountProperties,omitempty"`
+	CreatedTime   *int64                 `json:"createdTime,omitempty"`
+	RemoteAccess  *vaultRemoteAccess     `json:"remoteMachinesAccess,omitempty"`
+}
+
+// vaultAccountRequest is the body of the Accounts API for platforms without a typed set of properties.
+type vaultAccountRequest struct {
+	Name          *string                     `json:"name,omitempty"`
+	Address       *string                     `json:"address,omitempty"`
+	UserName      *string                     `json:"userName,omitempty"`
+	Platform      *string                     `json:"platformId"`
+	SafeName      *string                     `json:"safeName"`
+	SecretType    *string                     `json:"secretType,omitempty"`
+	Secret        *string                     `json:"secret,omitempty"`
+	SecretMgmt    *cybrtypes.SecretManagement `json:"secretManagement,omitempty"`
+	PlatformProps map[string]string           `json:"platformAccountProperties,omitempty"`
+	RemoteAccess  *vaultRemoteAccess          `json:"remoteMachinesAccess,omitempty"`
+}
+
+// vaultRemoteAccess restricts the machines an account can connect to.
+// Remote machines are a semicolon separated list.
+type vaultRemoteAccess struct {
+	RemoteMachines   string `json:"remoteMachines"`
+	AccessRestricted bool   `json:"accessRestrictedToRemoteMachines"`
+}
+
+// vaultPage is a single page returned by the vault list APIs.
+type vaultPage[T any] struct {
+	Value []T `json:"value"`
+	Count int `json:"count"`
+}
+
+// vaultPageSize is the maximum number of objects requested per page from list APIs.
+const vaultPageSize = 1000
+
+// listVaultObjects pages through a vault list API and returns every object matching query.
+func listVaultObjects[T any](ctx context.Context, c vaultClient, path string, query url.Values) ([]T, error) {
+
+	var objects []T
+
+	query.Set("limit", strconv.Itoa(vaultPageSize))
+
+	for {
+		query.Set("offset", strconv.Itoa(len(objects)))
+
+		var page vaultPage[T]
+
+		err := vaultRequest(ctx, c, http.MethodGet, path+"?"+query.Encode(), nil, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, page.Value...)
+
+		if len(page.Value) == 0 || len(objects) >= page.Count {
+			return objects, nil
+		}
+	}
+}
+
+// vaultSafe is a safe returned by the Safes list API.
+type vaultSafe struct {
+	cybrtypes.SafeData
+	CreationTime *int64           `json:"creationTime,omitempty"`
+	Creator      *vaultObjectRef  `json:"creator,omitempty"`
+	Accounts     []vaultObjectRef `json:"accounts,omitempty"`
+}
+
+// vaultObjectRef is a reference to a vault object by ID and name.
+type vaultObjectRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// vaultSafeMember is a member of a safe as used by the Safe Members API.
+// The expiration date is always sent so clearing it in configuration removes it from the vault.
+type vaultSafeMember struct {
+	Name           *string               `json:"memberName,omitempty"`
+	Type           *string               `json:"memberType,omitempty"`
+	SearchIn       *string               `json:"searchIn,omitempty"`
+	ExpirationDate *int64                `json:"membershipExpirationDate"`
+	Permissions    *cybrtypes.Permission `json:"permissions,omitempty"`
+}
+
+// secretRetrieveRequest is the body of the Accounts password retrieve API.
+type secretRetrieveRequest struct {
+	Reason          string `json:"reason,omitempty"`
+	TicketingSystem string `json:"TicketingSystemName,omitempty"`
+	TicketID        string `json:"TicketId,omitempty"`
+	Version         int64  `json:"Version,omitempty"`
+}
+
+// retrieveVaultSecret retrieves the secret value of an account.
+func retrieveVaultSecret(ctx context.Context, c vaultClient, id string, body *secretRetrieveRequest) (string, error) {
+
+	var secret string
+
+	err := vaultRequest(ctx, c, http.MethodPost, "Accounts/"+url.PathEscape(id)+"/Password/Retrieve", body, &secret)
+	if err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+// requiresApproval reports whether the vault refused a secret retrieval because the safe
+// enforces dual control, a ticketing system or a retrieval reason.
+func requiresApproval(err error) bool {
+
+	var vErr *vaultError
+
+	if !errors.As(err, &vErr) || vErr.StatusCode != http.StatusForbidden {
+		return false
+	}
+
+	msg := strings.ToLower(vErr.ErrorMessage)
+
+	for _, keyword := range []string{"confirm", "approv", "dual control", "ticket", "reason"} {
+		if strings.Contains(msg, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// secretUpdateRequest is the body of the Accounts password update API, which sets the secret in the vault only.
+type secretUpdateRequest struct {
+	ChangeEntireGroup bool   `json:"ChangeEntireGroup"`
+	NewCredentials    string `json:"NewCredentials"`
+}
+
+// updateVaultSecret replaces the secret of an account in the vault without changing it on the target machine.
+func updateVaultSecret(ctx context.Context, c vaultClient, id string, secret string) error {
+	return vaultRequest(ctx, c, http.MethodPost, "Accounts/"+url.PathEscape(id)+"/Password/Update", &secretUpdateRequest{NewCredentials: secret}, nil)
+}
+
+const (
+	// extraPasswordIndex of the logon account linked to an account.
+	linkedLogonAccount = 1
+	// extraPasswordIndex of the reconcile account linked to an account.
+	linkedReconcileAccount = 3
+)
+
+// vaultLinkedAccount is the body of the Accounts link account API.
+type vaultLinkedAccount struct {
+	Safe               string `json:"safe"`
+	ExtraPasswordIndex int    `json:"extraPasswordIndex"`
+	Name               string `json:"name"`
+	Folder             string `json:"folder"`
+}
+
+// linkVaultAccount links the account identified by link to an account as its logon or reconcile account.
+func linkVaultAccount(ctx context.Context, c vaultClient, id string, link *vaultLinkedAccount) error {
+	return vaultRequest(ctx, c, http.MethodPost, "Accounts/"+url.PathEscape(id)+"/LinkAccount", link, nil)
+}
+
+// unlinkVaultAccount removes the logon or reconcile account linked at extraPasswordIndex.
+// Links that no longer exist are treated as already removed.
+func unlinkVaultAccount(ctx context.Context, c vaultClient, id string, index int) error {
+
+	err := vaultRequest(ctx, c, http.MethodDelete, "Accounts/"+url.PathEscape(id)+"/LinkAccount/"+strconv.Itoa(index), nil, nil)
+	if isNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// vaultLinkedAccountDetails is an account linked to another account, as returned by the linked accounts API.
+type vaultLinkedAccountDetails struct {
+	Name               string `json:"Name"`
+	SafeName           string `json:"SafeName"`
+	FolderName         string `json:"FolderName"`
+	ExtraPasswordIndex int    `json:"extraPasswordIndex"`
+}
+
+// readLinkedVaultAccounts returns the logon, enable and reconcile accounts linked to an account.
+func readLinkedVaultAccounts(ctx context.Context, c vaultClient, id string) ([]vaultLinkedAccountDetails, error) {
+
+	var linked struct {
+		LinkedAccounts []vaultLinkedAccountDetails `json:"LinkedAccounts"`
+	}
+
+	err := vaultRequest(ctx, c, http.MethodGet, "ExtendedAccounts/"+url.PathEscape(id)+"/LinkedAccounts", nil, &linked)
+
+	return linked.LinkedAccounts, err
+}
+
+// vaultPatchOperation is a JSON patch operation of the Accounts update API.
+type vaultPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// patchVaultAccount applies JSON patch operations to the properties of an account.
+func patchVaultAccount(ctx context.Context, c vaultClient, id string, operations []vaultPatchOperation) error {
+	return vaultRequest(ctx, c, http.MethodPatch, "Accounts/"+url.PathEscape(id), operations, nil)
+}
+
+// vaultAccountPatch collects the patch operations of the account properties changed between state and plan.
+type vaultAccountPatch []vaultPatchOperation
+
+// set adds, replaces or removes the property at path when the planned value differs from the current one.
+// Values that are only known after apply are left unchanged.
+func (p *vaultAccountPatch) set(path string, planned attr.Value, current attr.Value, value interface{}) {
+
+	switch {
+	case planned.IsUnknown() || planned.Equal(current):
+		return
+	case planned.IsNull():
+		*p = append(*p, vaultPatchOperation{Op: "remove", Path: path})
+	case current.IsNull() || current.IsUnknown():
+		*p = append(*p, vaultPatchOperation{Op: "add", Path: path, Value: value})
+	default:
+		*p = append(*p, vaultPatchOperation{Op: "replace", Path: path, Value: value})
+	}
+}
+
+// setString patches a string property of the account.
+func (p *vaultAccountPatch) setString(path string, planned htypes.String, current htypes.String) {
+	p.set(path, planned, current, planned.ValueString())
+}
+
+// setBool patches a flag of the account.
+func (p *vaultAccountPatch) setBool(path string, planned htypes.Bool, current htypes.Bool) {
+	p.set(path, planned, current, planned.ValueBool())
+}
+
+// setPlatformProperties patches the platform account properties that differ between both sets.
+// Properties missing from planned are removed from the account.
+func (p *vaultAccountPatch) setPlatformProperties(planned map[string]string, current map[string]string) {
+
+	keys := make([]string, 0, len(planned)+len(current))
+
+	for k := range planned {
+		keys = append(keys, k)
+	}
+
+	for k := range current {
+		if _, ok := planned[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		plannedValue, ok := planned[k]
+		plannedProp := htypes.StringNull()
+		if ok {
+			plannedProp = htypes.StringValue(plannedValue)
+		}
+
+		currentValue, ok := current[k]
+		currentProp := htypes.StringNull()
+		if ok {
+			currentProp = htypes.StringValue(currentValue)
+		}
+
+		p.setString("/platformAccountProperties/"+k, plannedProp, currentProp)
+	}
+}
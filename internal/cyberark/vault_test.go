@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	htypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestVaultAccountPatchSetPlatformProperties(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		planned  map[string]string
+		current  map[string]string
+		expected vaultAccountPatch
+	}{
+		{
+			name:    "unchanged keys are not patched",
+			planned: map[string]string{"Port": "1433", "Database": "app"},
+			current: map[string]string{"Port": "1433", "Database": "app"},
+		},
+		{
+			name:     "added key",
+			planned:  map[string]string{"Port": "1433", "Database": "app"},
+			current:  map[string]string{"Port": "1433"},
+			expected: vaultAccountPatch{{Op: "add", Path: "/platformAccountProperties/Database", Value: "app"}},
+		},
+		{
+			name:     "changed key",
+			planned:  map[string]string{"Port": "1434"},
+			current:  map[string]string{"Port": "1433"},
+			expected: vaultAccountPatch{{Op: "replace", Path: "/platformAccountProperties/Port", Value: "1434"}},
+		},
+		{
+			name:     "removed key",
+			planned:  map[string]string{},
+			current:  map[string]string{"Port": "1433"},
+			expected: vaultAccountPatch{{Op: "remove", Path: "/platformAccountProperties/Port"}},
+		},
+		{
+			name:    "mixed changes are sorted by key",
+			planned: map[string]string{"Region": "us-east-1", "AWSAccountID": "123456789012", "Alias": "prod"},
+			current: map[string]string{"Region": "eu-west-1", "AWSAccountID": "123456789012", "Zone": "a"},
+			expected: vaultAccountPatch{
+				{Op: "add", Path: "/platformAccountProperties/Alias", Value: "prod"},
+				{Op: "replace", Path: "/platformAccountProperties/Region", Value: "us-east-1"},
+				{Op: "remove", Path: "/platformAccountProperties/Zone"},
+			},
+		},
+		{
+			name:     "no current properties",
+			planned:  map[string]string{"Port": "22"},
+			expected: vaultAccountPatch{{Op: "add", Path: "/platformAccountProperties/Port", Value: "22"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var patch vaultAccountPatch
+			patch.setPlatformProperties(tt.planned, tt.current)
+
+			if !reflect.DeepEqual(patch, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, patch)
+			}
+		})
+	}
+}
+
+func TestVaultAccountPatchSetString(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		planned  htypes.String
+		current  htypes.String
+		expected vaultAccountPatch
+	}{
+		{"unchanged", htypes.StringValue("db01"), htypes.StringValue("db01"), nil},
+		{"unknown until apply", htypes.StringUnknown(), htypes.StringValue("db01"), nil},
+		{"added", htypes.StringValue("db01"), htypes.StringNull(), vaultAccountPatch{{Op: "add", Path: "/address", Value: "db01"}}},
+		{"changed", htypes.StringValue("db02"), htypes.StringValue("db01"), vaultAccountPatch{{Op: "replace", Path: "/address", Value: "db02"}}},
+		{"removed", htypes.StringNull(), htypes.StringValue("db01"), vaultAccountPatch{{Op: "remove", Path: "/address"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var patch vaultAccountPatch
+			patch.setString("/address", tt.planned, tt.current)
+
+			if !reflect.DeepEqual(patch, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, patch)
+			}
+		})
+	}
+}